@@ -0,0 +1,329 @@
+package gocsv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charset is the character encoding of CSV input or output.
+type Charset int
+
+const (
+	// CharsetDefault leaves the input and output bytes as they are, which is
+	// UTF-8 for the output.
+	CharsetDefault Charset = iota
+	// CharsetAuto detects the encoding of the input from its byte order mark,
+	// or else from its first bytes (see Config.CharsetSampleSize): UTF-16 when
+	// most other bytes are zero, UTF-8 when valid, and Windows-1252 otherwise.
+	// It is UTF-8 for the output.
+	CharsetAuto
+	// CharsetUTF8 is UTF-8. A byte order mark is stripped from the input.
+	CharsetUTF8
+	// CharsetUTF16LE is little-endian UTF-16. A byte order mark is stripped from the input.
+	CharsetUTF16LE
+	// CharsetUTF16BE is big-endian UTF-16. A byte order mark is stripped from the input.
+	CharsetUTF16BE
+	// CharsetLatin1 is ISO-8859-1.
+	CharsetLatin1
+	// CharsetWindows1252 is Windows-1252, the Latin-1 superset used by Excel on Windows.
+	CharsetWindows1252
+)
+
+func (c Charset) String() string {
+	switch c {
+	case CharsetDefault:
+		return "default"
+	case CharsetAuto:
+		return "auto"
+	case CharsetUTF8:
+		return "UTF-8"
+	case CharsetUTF16LE:
+		return "UTF-16LE"
+	case CharsetUTF16BE:
+		return "UTF-16BE"
+	case CharsetLatin1:
+		return "ISO-8859-1"
+	case CharsetWindows1252:
+		return "Windows-1252"
+	}
+	return fmt.Sprintf("Charset(%d)", int(c))
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// windows1252 holds the runes of the Windows-1252 bytes 0x80 to 0x9F, which
+// differ from Latin-1. The unassigned bytes are mapped to the Latin-1 control
+// characters, as web browsers do.
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡',
+	'ˆ', '‰', 'Š', '‹', 'Œ', '\u008D', 'Ž', '\u008F',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—',
+	'˜', '™', 'š', '›', 'œ', '\u009D', 'ž', 'Ÿ',
+}
+
+// windows1252Bytes maps the runes of windows1252 back to their byte.
+var windows1252Bytes = func() map[rune]byte {
+	m := make(map[rune]byte, len(windows1252))
+	for i, r := range windows1252 {
+		m[r] = byte(0x80 + i)
+	}
+	return m
+}()
+
+// decodeInput returns a reader of the UTF-8 text of in, whose encoding is
+// given by Config.Charset.
+func (cfg *Config) decodeInput(in io.Reader) io.Reader {
+	if cfg.Charset == CharsetDefault {
+		return in
+	}
+	r := bufio.NewReader(in)
+	if cfg.CharsetSampleSize > r.Size() {
+		r = bufio.NewReaderSize(in, cfg.CharsetSampleSize)
+	}
+	charset := cfg.Charset
+	// only wait for the bytes of a byte order mark, or of the end of the input
+	sample, _ := r.Peek(len(bomUTF8))
+	switch {
+	case bytes.HasPrefix(sample, bomUTF8):
+		charset = CharsetUTF8
+		r.Discard(len(bomUTF8))
+	case bytes.HasPrefix(sample, bomUTF16LE):
+		charset = CharsetUTF16LE
+		r.Discard(len(bomUTF16LE))
+	case bytes.HasPrefix(sample, bomUTF16BE):
+		charset = CharsetUTF16BE
+		r.Discard(len(bomUTF16BE))
+	case charset == CharsetAuto:
+		if cfg.CharsetSampleSize > 0 {
+			sample, _ = r.Peek(cfg.CharsetSampleSize)
+		} else {
+			sample, _ = r.Peek(r.Buffered())
+		}
+		charset = detectCharset(sample)
+	}
+	switch charset {
+	case CharsetUTF16LE, CharsetUTF16BE:
+		bigEndian := charset == CharsetUTF16BE
+		return &decodingReader{in: r, decode: func(in *bufio.Reader) (rune, error) {
+			return readUTF16(in, bigEndian)
+		}}
+	case CharsetLatin1:
+		return &decodingReader{in: r, decode: func(in *bufio.Reader) (rune, error) {
+			b, err := in.ReadByte()
+			return rune(b), err
+		}}
+	case CharsetWindows1252:
+		return &decodingReader{in: r, decode: func(in *bufio.Reader) (rune, error) {
+			b, err := in.ReadByte()
+			if b >= 0x80 && b < 0xA0 {
+				return windows1252[b-0x80], err
+			}
+			return rune(b), err
+		}}
+	}
+	return r
+}
+
+// detectCharset guesses the charset of the input from its first bytes.
+func detectCharset(sample []byte) Charset {
+	pairs := len(sample) / 2
+	zerosEven, zerosOdd := 0, 0
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			zerosEven++
+		}
+		if sample[i+1] == 0 {
+			zerosOdd++
+		}
+	}
+	// ASCII text in UTF-16 has a zero byte in every other byte
+	if zerosOdd > pairs/2 && zerosEven < zerosOdd/8 {
+		return CharsetUTF16LE
+	} else if zerosEven > pairs/2 && zerosOdd < zerosEven/8 {
+		return CharsetUTF16BE
+	}
+	if isValidUTF8Sample(sample) {
+		return CharsetUTF8
+	}
+	return CharsetWindows1252
+}
+
+// isValidUTF8Sample tells whether sample is valid UTF-8, a rune cut at the
+// end of the sample being accepted.
+func isValidUTF8Sample(sample []byte) bool {
+	for len(sample) > 0 {
+		r, size := utf8.DecodeRune(sample)
+		if r == utf8.RuneError && size == 1 {
+			return len(sample) < utf8.UTFMax && !utf8.FullRune(sample)
+		}
+		sample = sample[size:]
+	}
+	return true
+}
+
+// readUTF16 reads a UTF-16 encoded rune. Invalid surrogates and a trailing
+// odd byte are read as utf8.RuneError.
+func readUTF16(in *bufio.Reader, bigEndian bool) (rune, error) {
+	readUnit := func() (rune, error) {
+		var b [2]byte
+		n, err := io.ReadFull(in, b[:])
+		if err == io.ErrUnexpectedEOF {
+			return utf8.RuneError, nil
+		} else if n == 0 {
+			return 0, err
+		}
+		if bigEndian {
+			return rune(b[0])<<8 | rune(b[1]), nil
+		}
+		return rune(b[1])<<8 | rune(b[0]), nil
+	}
+	r, err := readUnit()
+	if err != nil || !utf16.IsSurrogate(r) {
+		return r, err
+	}
+	if r >= 0xDC00 { // low surrogate without high surrogate
+		return utf8.RuneError, nil
+	}
+	next, err := in.Peek(2)
+	if err != nil || len(next) < 2 {
+		return utf8.RuneError, nil
+	}
+	low := rune(next[1])<<8 | rune(next[0])
+	if bigEndian {
+		low = rune(next[0])<<8 | rune(next[1])
+	}
+	if low < 0xDC00 || low > 0xDFFF {
+		return utf8.RuneError, nil
+	}
+	in.Discard(2)
+	return utf16.DecodeRune(r, low), nil
+}
+
+// decodingReader reads the runes decoded from in as UTF-8.
+type decodingReader struct {
+	in     *bufio.Reader
+	decode func(in *bufio.Reader) (rune, error)
+	buf    []byte // Decoded bytes not read yet
+	err    error
+}
+
+func (r *decodingReader) Read(p []byte) (int, error) {
+	for len(r.buf) < len(p) && r.err == nil {
+		if len(r.buf) > 0 && r.in.Buffered() == 0 {
+			break // return what was decoded rather than wait for more input
+		}
+		c, err := r.decode(r.in)
+		if err != nil {
+			r.err = err
+			break
+		}
+		r.buf = utf8.AppendRune(r.buf, c)
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	if len(r.buf) == 0 && r.err != nil {
+		if n > 0 {
+			return n, nil
+		}
+		return 0, r.err
+	}
+	return n, nil
+}
+
+// encodeOutput returns a writer encoding the UTF-8 text written to it into
+// Config.OutputCharset, preceded by a byte order mark with Config.WriteBOM.
+func (cfg *Config) encodeOutput(out io.Writer) io.Writer {
+	var bom []byte
+	var encode func(dst []byte, r rune) []byte
+	switch cfg.OutputCharset {
+	case CharsetUTF16LE, CharsetUTF16BE:
+		bigEndian := cfg.OutputCharset == CharsetUTF16BE
+		if bom = bomUTF16LE; bigEndian {
+			bom = bomUTF16BE
+		}
+		encode = func(dst []byte, r rune) []byte {
+			return appendUTF16(dst, r, bigEndian)
+		}
+	case CharsetLatin1:
+		encode = func(dst []byte, r rune) []byte {
+			if r >= 0x100 {
+				r = '?'
+			}
+			return append(dst, byte(r))
+		}
+	case CharsetWindows1252:
+		encode = func(dst []byte, r rune) []byte {
+			if r < 0x80 || (r >= 0xA0 && r < 0x100) {
+				return append(dst, byte(r))
+			} else if b, ok := windows1252Bytes[r]; ok {
+				return append(dst, b)
+			}
+			return append(dst, '?')
+		}
+	default:
+		bom = bomUTF8
+	}
+	if !cfg.WriteBOM {
+		bom = nil
+	}
+	if encode == nil && bom == nil {
+		return out
+	}
+	return &encodingWriter{out: out, encode: encode, bom: bom}
+}
+
+// appendUTF16 appends the UTF-16 encoding of r to dst.
+func appendUTF16(dst []byte, r rune, bigEndian bool) []byte {
+	units := []rune{r}
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		units = []rune{r1, r2}
+	} else if r > 0xFFFF || utf16.IsSurrogate(r) {
+		units = []rune{utf8.RuneError}
+	}
+	for _, u := range units {
+		if bigEndian {
+			dst = append(dst, byte(u>>8), byte(u))
+		} else {
+			dst = append(dst, byte(u), byte(u>>8))
+		}
+	}
+	return dst
+}
+
+// encodingWriter encodes the UTF-8 text written to it with encode, or writes
+// it as is when encode is nil, after writing bom.
+type encodingWriter struct {
+	out     io.Writer
+	encode  func(dst []byte, r rune) []byte
+	bom     []byte // Written before the first bytes
+	pending []byte // Start of a rune cut by the previous Write
+	buf     []byte
+}
+
+func (w *encodingWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf[:0], w.bom...)
+	w.bom = nil
+	if w.encode == nil {
+		w.buf = append(w.buf, p...)
+	} else {
+		data := append(w.pending, p...)
+		for len(data) > 0 && utf8.FullRune(data) {
+			r, size := utf8.DecodeRune(data)
+			w.buf = w.encode(w.buf, r)
+			data = data[size:]
+		}
+		w.pending = append(w.pending[:0:0], data...)
+	}
+	if _, err := w.out.Write(w.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
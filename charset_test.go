@@ -0,0 +1,168 @@
+package gocsv
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+type charsetSample struct {
+	Name string `csv:"name"`
+	City string `csv:"city"`
+}
+
+func encodeUTF16(s string, bigEndian bool) []byte {
+	var b []byte
+	for _, r := range s {
+		b = appendUTF16(b, r, bigEndian)
+	}
+	return b
+}
+
+func TestCharsetDecode(t *testing.T) {
+	const text = "name,city\nZoë,Besançon €\nGrin,😀\n"
+	tests := []struct {
+		name    string
+		in      []byte
+		charset Charset
+	}{
+		{"UTF-8 BOM", append([]byte{0xEF, 0xBB, 0xBF}, text...), CharsetAuto},
+		{"UTF-8 BOM explicit", append([]byte{0xEF, 0xBB, 0xBF}, text...), CharsetUTF8},
+		{"UTF-16LE BOM", append([]byte{0xFF, 0xFE}, encodeUTF16(text, false)...), CharsetAuto},
+		{"UTF-16BE BOM", append([]byte{0xFE, 0xFF}, encodeUTF16(text, true)...), CharsetAuto},
+		{"UTF-16LE without BOM", encodeUTF16(text, false), CharsetAuto},
+		{"UTF-16BE explicit", encodeUTF16(text, true), CharsetUTF16BE},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var samples []charsetSample
+			if err := UnmarshalBytes(test.in, &samples, WithCharset(test.charset)); err != nil {
+				t.Fatal(err)
+			}
+			if len(samples) != 2 || samples[0].Name != "Zoë" || samples[0].City != "Besançon €" || samples[1].City != "😀" {
+				t.Fatalf("unexpected samples %+v", samples)
+			}
+		})
+	}
+}
+
+func TestCharsetDecodeStreaming(t *testing.T) {
+	for _, charset := range []Charset{CharsetAuto, CharsetUTF8, CharsetWindows1252} {
+		t.Run(charset.String(), func(t *testing.T) {
+			in, out := io.Pipe()
+			defer out.Close()
+			go out.Write([]byte("name,city\nZoe,Paris\n"))
+
+			// the first record is decoded while the input is still open
+			done := make(chan error, 1)
+			go func() {
+				d, err := NewRowDecoder(in, WithCharset(charset))
+				if err == nil && d.Next() {
+					var s charsetSample
+					err = d.Decode(&s)
+				}
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatal(err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("the decoding waited for more input")
+			}
+		})
+	}
+
+	// a larger sample is waited for when asked
+	in := append([]byte("name,city\n"), bytes.Repeat([]byte("Zoe,Paris\n"), 100)...)
+	in = append(in, "Zo\xeb,Besan\xe7on\n"...)
+	var samples []charsetSample
+	if err := Unmarshal(&oneByteReader{in}, &samples, WithCharset(CharsetAuto), WithCharsetSampleSize(len(in))); err != nil {
+		t.Fatal(err)
+	}
+	if last := samples[len(samples)-1]; last.Name != "Zoë" || last.City != "Besançon" {
+		t.Fatalf("unexpected sample %+v", last)
+	}
+}
+
+// oneByteReader returns its input one byte at a time.
+type oneByteReader struct {
+	in []byte
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(r.in) == 0 {
+		return 0, io.EOF
+	} else if len(p) == 0 {
+		return 0, nil
+	}
+	p[0], r.in = r.in[0], r.in[1:]
+	return 1, nil
+}
+
+func TestCharsetDecodeSingleByte(t *testing.T) {
+	in := []byte("name,city\nZo\xeb,Besan\xe7on \x80\n")
+
+	var samples []charsetSample
+	if err := UnmarshalBytes(in, &samples, WithCharset(CharsetAuto)); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Name != "Zoë" || samples[0].City != "Besançon €" {
+		t.Fatalf("unexpected sample %+v", samples[0])
+	}
+
+	samples = nil
+	if err := UnmarshalBytes(in, &samples, WithCharset(CharsetLatin1)); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].City != "Besançon \u0080" {
+		t.Fatalf("unexpected sample %+v", samples[0])
+	}
+
+	// without charset, the bytes are read as they are
+	samples = nil
+	if err := UnmarshalBytes(in, &samples); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Name != "Zo\xeb" {
+		t.Fatalf("unexpected sample %+v", samples[0])
+	}
+}
+
+func TestCharsetEncode(t *testing.T) {
+	samples := []charsetSample{{Name: "Zoë", City: "€ 😀"}}
+
+	out, err := MarshalBytes(samples, WithBOM())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "\xEF\xBB\xBFname,city\nZoë,€ 😀\n"; string(out) != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+
+	out, err = MarshalBytes(samples, WithOutputCharset(CharsetUTF16LE), WithBOM())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := append([]byte{0xFF, 0xFE}, encodeUTF16("name,city\nZoë,€ 😀\n", false)...)
+	if !bytes.Equal(out, expected) {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+	var decoded []charsetSample
+	if err := UnmarshalBytes(out, &decoded, WithCharset(CharsetAuto)); err != nil {
+		t.Fatal(err)
+	}
+	if decoded[0] != samples[0] {
+		t.Fatalf("expected %+v, got %+v", samples[0], decoded[0])
+	}
+
+	out, err = MarshalBytes(samples, WithOutputCharset(CharsetWindows1252), WithBOM())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "name,city\nZo\xeb,\x80 ?\n"; string(out) != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+}
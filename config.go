@@ -0,0 +1,431 @@
+package gocsv
+
+import (
+	"io"
+)
+
+// Config holds the settings used by a single Unmarshal*, Marshal* or
+// Unmarshaller call. It lets several parts of a program use different
+// settings without touching the package-level variables, which only act as
+// defaults (see DefaultConfig).
+type Config struct {
+	// FailIfUnmatchedStructTags indicates whether it is considered an error when there is an unmatched
+	// struct tag.
+	FailIfUnmatchedStructTags bool
+
+	// FailIfDoubleHeaderNames indicates whether it is considered an error when a header name is repeated
+	// in the csv header.
+	FailIfDoubleHeaderNames bool
+
+	// FailIfShortRecords indicates whether it is considered an error when a record has fewer fields
+	// than the header, or than the struct fields it is mapped to without header. Such a record
+	// otherwise leaves the fields of its missing columns unset.
+	FailIfShortRecords bool
+
+	// ShouldAlignDuplicateHeadersWithStructFieldOrder indicates whether we should align duplicate CSV
+	// headers per their alignment in the struct definition.
+	ShouldAlignDuplicateHeadersWithStructFieldOrder bool
+
+	// TagName defines key in the struct field's tag to scan. Defaults to "csv" when empty.
+	TagName string
+
+	// TagSeparator defines seperator string for multiple csv tags in struct fields. Defaults to "," when empty.
+	TagSeparator string
+
+	// FieldsCombiner defines how to combine parent struct with child struct. Defaults to "." when empty.
+	FieldsCombiner string
+
+	// HeaderNormalizer is applied to struct and header field names before they are compared.
+	// Names are compared as is when nil.
+	HeaderNormalizer Normalizer
+
+	// CSVReader creates the CSV reader used to parse CSV. Defaults to DefaultCSVReader when nil.
+	CSVReader func(io.Reader) CSVReader
+
+	// CSVWriter creates the SafeCSVWriter used to format CSV. When nil, a writer
+	// using the first rune of TagSeparator as separator is used (cf. DefaultCSVWriter).
+	CSVWriter func(io.Writer) *SafeCSVWriter
+
+	// Dialect, when set, is the format of the CSV, read and written with
+	// NewDialectCSVReader and NewDialectCSVWriter instead of CSVReader and
+	// CSVWriter. The output delimiter then no longer follows TagSeparator.
+	Dialect *Dialect
+
+	// CollectErrors makes decoding go on after a cell fails to decode, leaving the
+	// field unset, and return every failure at the end as a *DecodeErrors.
+	// Errors accepted by an ErrorHandler are not collected. It applies to the
+	// decoding with or without header, but not to the Unmarshaller, which
+	// refuses it.
+	CollectErrors bool
+
+	// MaxErrors stops decoding in collect mode once more errors than MaxErrors
+	// were found. There is no limit when it is zero or negative.
+	MaxErrors int
+
+	// NullTokens are the cell values standing for a null value, e.g. "NULL" or "\\N".
+	// A null leaves pointers nil, sets sql.Null* types to Valid=false and other
+	// fields to their zero value. When encoding, nil values are written as the
+	// first token. The null tag option, e.g. `csv:"name,null=N/A"`, overrides
+	// them for a single field.
+	NullTokens []string
+
+	// Workers is the number of goroutines converting records into values while
+	// another goroutine reads them. Values are still produced in input order,
+	// and errors report the line of their record. Records are converted one at
+	// a time by the calling goroutine when it is 0 or 1. Custom unmarshallers
+	// (UnmarshalCSV, UnmarshalText...) must be safe for concurrent use when it
+	// is above 1.
+	Workers int
+
+	// SkipLines is the number of lines skipped at the start of the input,
+	// before the header row, e.g. a title or "Generated on" lines.
+	SkipLines int
+
+	// SkipUntil, when set, skips the lines, after the SkipLines ones, until the
+	// first line for which it returns true. This line is the header row. The
+	// line is given without its line terminator.
+	SkipUntil func(line string) bool
+
+	// DetectHeader skips the lines before the header row, found as the first
+	// line matching the most struct keys among the first lines of the input.
+	// The input is read from its start when no line matches. It is done after
+	// SkipLines, unless SkipUntil found the header row, and only when the
+	// struct type is known before reading the header (i.e. not by RowDecoder).
+	DetectHeader bool
+
+	// PreambleHandler, when set, receives the lines skipped before the header
+	// row by SkipLines, SkipUntil and DetectHeader, without their line terminator.
+	//
+	// The preamble options only apply when reading from an io.Reader, as the
+	// lines are skipped before being parsed.
+	PreambleHandler func(lines []string)
+
+	// TrailerRecords is the number of records set apart at the end of the
+	// input, e.g. totals or an "END OF FILE" line. An input holding no more
+	// rows, e.g. a header only, is rejected with ErrShortTrailerInput.
+	TrailerRecords int
+
+	// TrailerStart, when set, stops the decoding at the first record for which
+	// it returns true: this record and the following ones are the trailer.
+	TrailerStart func(record []string) bool
+
+	// TrailerHandler, when set, receives the trailer records once the other
+	// records were read, e.g. to check control totals. The error it returns, if
+	// any, is returned by the decoding.
+	//
+	// Trailer records with an unexpected number of fields are accepted. The
+	// trailer options only apply when reading from an io.Reader.
+	TrailerHandler func(records [][]string) error
+
+	// Charset is the character encoding of the input, which is transcoded to
+	// UTF-8 before being parsed. The input is read as is with CharsetDefault.
+	// It only applies when reading from an io.Reader.
+	Charset Charset
+
+	// CharsetSampleSize is the number of bytes CharsetAuto waits for to detect
+	// the charset of an input without byte order mark. When zero, only the
+	// bytes returned by the first read are looked at, so that streaming input
+	// is not stalled.
+	CharsetSampleSize int
+
+	// OutputCharset is the character encoding of the output, UTF-8 with
+	// CharsetDefault. It only applies when writing to an io.Writer.
+	OutputCharset Charset
+
+	// WriteBOM writes a byte order mark at the start of UTF-8 and UTF-16
+	// output, as expected by Excel.
+	WriteBOM bool
+
+	// Quoting tells which fields are quoted when writing, QuoteMinimal by
+	// default. The quote tag option overrides it for a field, e.g.
+	// `csv:"name,quote"` or `csv:"id,quote=none"`. Policies other than
+	// QuoteMinimal need the writers created by gocsv.
+	Quoting QuotePolicy
+
+	// AutoDialect sniffs the delimiter and the quote of the input (see Sniff)
+	// before parsing it. It only applies with a Dialect, whose delimiter and
+	// quote are replaced, or when the CSV reader is a *csv.Reader, as created
+	// by DefaultCSVReader and LazyCSVReader, replaced by a DialectReader when
+	// the quote is not '"', and when reading from an io.Reader.
+	AutoDialect bool
+}
+
+// Option modifies the Config of a single call.
+type Option func(*Config)
+
+// DefaultConfig returns a Config holding the current package-level settings
+// (FailIfUnmatchedStructTags, TagName, SetHeaderNormalizer, SetCSVReader...).
+// It is the starting point of every call before its options are applied.
+func DefaultConfig() Config {
+	return Config{
+		FailIfUnmatchedStructTags:                       FailIfUnmatchedStructTags,
+		FailIfDoubleHeaderNames:                         FailIfDoubleHeaderNames,
+		ShouldAlignDuplicateHeadersWithStructFieldOrder: ShouldAlignDuplicateHeadersWithStructFieldOrder,
+		TagName:          TagName,
+		TagSeparator:     TagSeparator,
+		FieldsCombiner:   FieldsCombiner,
+		HeaderNormalizer: normalizeName,
+		CSVReader:        selfCSVReader,
+		CSVWriter:        selfCSVWriter,
+		NullTokens:       NullTokens,
+	}
+}
+
+// newConfig returns the default config with the given options applied.
+func newConfig(opts []Option) *Config {
+	cfg := DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.TagName == "" {
+		cfg.TagName = "csv"
+	}
+	if cfg.TagSeparator == "" {
+		cfg.TagSeparator = ","
+	}
+	if cfg.FieldsCombiner == "" {
+		cfg.FieldsCombiner = "."
+	}
+	if cfg.CSVReader == nil {
+		cfg.CSVReader = DefaultCSVReader
+	}
+	return &cfg
+}
+
+// WithConfig replaces the whole config of the call by c.
+func WithConfig(c Config) Option {
+	return func(cfg *Config) {
+		*cfg = c
+	}
+}
+
+// WithFailIfUnmatchedStructTags sets Config.FailIfUnmatchedStructTags.
+func WithFailIfUnmatchedStructTags(fail bool) Option {
+	return func(cfg *Config) {
+		cfg.FailIfUnmatchedStructTags = fail
+	}
+}
+
+// WithFailIfDoubleHeaderNames sets Config.FailIfDoubleHeaderNames.
+func WithFailIfDoubleHeaderNames(fail bool) Option {
+	return func(cfg *Config) {
+		cfg.FailIfDoubleHeaderNames = fail
+	}
+}
+
+// WithFailIfShortRecords sets Config.FailIfShortRecords.
+func WithFailIfShortRecords(fail bool) Option {
+	return func(cfg *Config) {
+		cfg.FailIfShortRecords = fail
+	}
+}
+
+// WithAlignDuplicateHeadersWithStructFieldOrder sets Config.ShouldAlignDuplicateHeadersWithStructFieldOrder.
+func WithAlignDuplicateHeadersWithStructFieldOrder(align bool) Option {
+	return func(cfg *Config) {
+		cfg.ShouldAlignDuplicateHeadersWithStructFieldOrder = align
+	}
+}
+
+// WithTagName sets Config.TagName.
+func WithTagName(tagName string) Option {
+	return func(cfg *Config) {
+		cfg.TagName = tagName
+	}
+}
+
+// WithTagSeparator sets Config.TagSeparator.
+func WithTagSeparator(separator string) Option {
+	return func(cfg *Config) {
+		cfg.TagSeparator = separator
+	}
+}
+
+// WithFieldsCombiner sets Config.FieldsCombiner.
+func WithFieldsCombiner(combiner string) Option {
+	return func(cfg *Config) {
+		cfg.FieldsCombiner = combiner
+	}
+}
+
+// WithHeaderNormalizer sets Config.HeaderNormalizer.
+func WithHeaderNormalizer(f Normalizer) Option {
+	return func(cfg *Config) {
+		cfg.HeaderNormalizer = f
+	}
+}
+
+// WithCSVReader sets Config.CSVReader.
+func WithCSVReader(csvReader func(io.Reader) CSVReader) Option {
+	return func(cfg *Config) {
+		cfg.CSVReader = csvReader
+	}
+}
+
+// WithCSVWriter sets Config.CSVWriter.
+func WithCSVWriter(csvWriter func(io.Writer) *SafeCSVWriter) Option {
+	return func(cfg *Config) {
+		cfg.CSVWriter = csvWriter
+	}
+}
+
+// WithDialect sets Config.Dialect.
+func WithDialect(d Dialect) Option {
+	return func(cfg *Config) {
+		cfg.Dialect = &d
+	}
+}
+
+// WithCollectErrors enables collect mode, returning at most maxErrors errors (see Config.CollectErrors).
+func WithCollectErrors(maxErrors int) Option {
+	return func(cfg *Config) {
+		cfg.CollectErrors = true
+		cfg.MaxErrors = maxErrors
+	}
+}
+
+// WithNullTokens sets Config.NullTokens.
+func WithNullTokens(tokens ...string) Option {
+	return func(cfg *Config) {
+		cfg.NullTokens = tokens
+	}
+}
+
+// WithWorkers sets Config.Workers.
+func WithWorkers(n int) Option {
+	return func(cfg *Config) {
+		cfg.Workers = n
+	}
+}
+
+// WithSkipLines sets Config.SkipLines.
+func WithSkipLines(n int) Option {
+	return func(cfg *Config) {
+		cfg.SkipLines = n
+	}
+}
+
+// WithSkipUntil sets Config.SkipUntil.
+func WithSkipUntil(isHeader func(line string) bool) Option {
+	return func(cfg *Config) {
+		cfg.SkipUntil = isHeader
+	}
+}
+
+// WithDetectHeader sets Config.DetectHeader.
+func WithDetectHeader() Option {
+	return func(cfg *Config) {
+		cfg.DetectHeader = true
+	}
+}
+
+// WithPreambleHandler sets Config.PreambleHandler.
+func WithPreambleHandler(f func(lines []string)) Option {
+	return func(cfg *Config) {
+		cfg.PreambleHandler = f
+	}
+}
+
+// WithTrailerRecords sets Config.TrailerRecords.
+func WithTrailerRecords(n int) Option {
+	return func(cfg *Config) {
+		cfg.TrailerRecords = n
+	}
+}
+
+// WithTrailerStart sets Config.TrailerStart.
+func WithTrailerStart(isTrailer func(record []string) bool) Option {
+	return func(cfg *Config) {
+		cfg.TrailerStart = isTrailer
+	}
+}
+
+// WithTrailerHandler sets Config.TrailerHandler.
+func WithTrailerHandler(f func(records [][]string) error) Option {
+	return func(cfg *Config) {
+		cfg.TrailerHandler = f
+	}
+}
+
+// WithTrailer sets Config.TrailerHandler to decode the trailer records into
+// out, a pointer to a slice of structs, or a pointer to a struct for a trailer
+// of a single record; ErrNoTrailer is then returned if there is none. The
+// columns are mapped to the fields by position, like with UnmarshalWithoutHeaders.
+func WithTrailer(out interface{}) Option {
+	return func(cfg *Config) {
+		cfg.TrailerHandler = func(records [][]string) error {
+			return decodeTrailer(cfg, records, out)
+		}
+	}
+}
+
+// WithCharset sets Config.Charset.
+func WithCharset(charset Charset) Option {
+	return func(cfg *Config) {
+		cfg.Charset = charset
+	}
+}
+
+// WithCharsetSampleSize sets Config.CharsetSampleSize.
+func WithCharsetSampleSize(size int) Option {
+	return func(cfg *Config) {
+		cfg.CharsetSampleSize = size
+	}
+}
+
+// WithOutputCharset sets Config.OutputCharset.
+func WithOutputCharset(charset Charset) Option {
+	return func(cfg *Config) {
+		cfg.OutputCharset = charset
+	}
+}
+
+// WithBOM sets Config.WriteBOM.
+func WithBOM() Option {
+	return func(cfg *Config) {
+		cfg.WriteBOM = true
+	}
+}
+
+// WithQuoting sets Config.Quoting.
+func WithQuoting(policy QuotePolicy) Option {
+	return func(cfg *Config) {
+		cfg.Quoting = policy
+	}
+}
+
+// WithAutoDialect sets Config.AutoDialect.
+func WithAutoDialect() Option {
+	return func(cfg *Config) {
+		cfg.AutoDialect = true
+	}
+}
+
+// normalize applies the header normalizer of the config to name.
+func (cfg *Config) normalize(name string) string {
+	if cfg.HeaderNormalizer == nil {
+		return name
+	}
+	return cfg.HeaderNormalizer(name)
+}
+
+func (cfg *Config) getCSVReader(in io.Reader) CSVReader {
+	if cfg.AutoDialect {
+		return cfg.getSniffedCSVReader(in)
+	}
+	if cfg.Dialect != nil {
+		return NewDialectCSVReader(in, *cfg.Dialect)
+	}
+	return cfg.CSVReader(in)
+}
+
+func (cfg *Config) getCSVWriter(out io.Writer) *SafeCSVWriter {
+	out = cfg.encodeOutput(out)
+	if cfg.Dialect != nil {
+		return NewDialectCSVWriter(out, *cfg.Dialect)
+	}
+	if cfg.CSVWriter == nil {
+		return newCSVWriter(out, cfg.TagSeparator)
+	}
+	return cfg.CSVWriter(out)
+}
@@ -0,0 +1,136 @@
+package gocsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConfigTagName(t *testing.T) {
+	var samples []CustomTagSample
+	if err := UnmarshalString("foo,BAR\nabc,def", &samples, WithTagName("custom")); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Foo != "abc" {
+		t.Fatalf("expected Foo to be read with the custom tag, got %+v", samples)
+	}
+	if TagName != "csv" {
+		t.Fatalf("the package-level TagName should not change, got %q", TagName)
+	}
+
+	samples = nil
+	if err := UnmarshalString("foo,BAR\nabc,def", &samples); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Foo != "" || samples[0].Bar != "def" {
+		t.Fatalf("expected the default tag name to be used, got %+v", samples)
+	}
+}
+
+func TestConfigTagSeparator(t *testing.T) {
+	var samples []TagSeparatorSample
+	if err := UnmarshalString("foo,BAR\nabc,123", &samples, WithTagSeparator("|")); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Foo != "abc" || samples[0].Bar != 123 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+
+	// The default writer uses the first rune of the tag separator as delimiter.
+	out, err := MarshalString(samples, WithTagSeparator("|"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Baz|BAR\nabc|123\n"; out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+}
+
+func TestConfigHeaderNormalizerIsolation(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var samples []Sample
+			err := UnmarshalString("FOO,BAR\nf,1", &samples, WithHeaderNormalizer(strings.ToLower))
+			if err == nil && (len(samples) != 1 || samples[0].Foo != "f") {
+				err = errors.New("lower case normalizer was not applied")
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			var samples []Sample
+			err := UnmarshalString("FOO,BAR\nf,1", &samples)
+			if err == nil && (len(samples) != 1 || samples[0].Foo != "" || samples[0].Bar != 1) {
+				err = errors.New("default config should not normalize headers")
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestConfigFailIfUnmatchedStructTags(t *testing.T) {
+	var samples []Sample
+	err := UnmarshalString("foo\nf", &samples, WithFailIfUnmatchedStructTags(true))
+	if !errors.Is(err, ErrUnmatchedStructTags) {
+		t.Fatalf("expected ErrUnmatchedStructTags, got %v", err)
+	}
+	if err := UnmarshalString("foo\nf", &samples); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigCSVReaderAndWriter(t *testing.T) {
+	pipeReader := func(in io.Reader) CSVReader {
+		r := csv.NewReader(in)
+		r.Comma = '|'
+		return r
+	}
+	var samples []Sample
+	if err := UnmarshalString("foo|BAR\nf|1", &samples, WithCSVReader(pipeReader)); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Foo != "f" || samples[0].Bar != 1 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+
+	tabWriter := func(out io.Writer) *SafeCSVWriter {
+		w := csv.NewWriter(out)
+		w.Comma = '\t'
+		return NewSafeCSVWriter(w)
+	}
+	b := bytes.Buffer{}
+	if err := Marshal([]MultiTagSample{{Foo: "a", Bar: 1}}, &b, WithCSVWriter(tabWriter)); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Baz\tBAR\na\t1\n"; b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestWithConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TagName = "custom"
+	cfg.HeaderNormalizer = strings.ToLower
+	var samples []CustomTagSample
+	if err := UnmarshalString("FOO,BAR\nabc,def", &samples, WithConfig(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Foo != "abc" {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+}
@@ -0,0 +1,163 @@
+package gocsv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// endlessReader is a CSV input of parallelSample rows which never ends.
+type endlessReader struct {
+	header bool
+	row    int
+	buf    []byte
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if !r.header {
+			r.header = true
+			r.buf = []byte("id,name\n")
+		} else {
+			r.buf = []byte(fmt.Sprintf("%d,name%d\n", r.row, r.row))
+			r.row++
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// cancelReader cancels a context when read, once armed.
+type cancelReader struct {
+	io.Reader
+	cancel func()
+	armed  bool
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	if r.armed {
+		r.cancel()
+	}
+	return r.Reader.Read(p)
+}
+
+// checkGoroutines fails the test if more goroutines run than before, once the
+// exiting ones had the time to return.
+func checkGoroutines(t *testing.T, before int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if runtime.NumGoroutine() <= before {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("goroutines leaked: %d before, %d after", before, runtime.NumGoroutine())
+}
+
+func TestUnmarshalToChanContext(t *testing.T) {
+	for _, workers := range []int{0, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			before := runtime.NumGoroutine()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c := make(chan parallelSample)
+			errs := make(chan error, 1)
+			go func() {
+				errs <- UnmarshalToChanContext(ctx, &endlessReader{}, c, WithWorkers(workers))
+			}()
+			for i := 0; i < 10; i++ {
+				if s := <-c; s.ID != i {
+					t.Fatalf("expected sample %d, got %+v", i, s)
+				}
+			}
+			cancel()
+			if err := <-errs; !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+			if _, ok := <-c; ok {
+				t.Fatal("expected the channel to be closed")
+			}
+			checkGoroutines(t, before)
+		})
+	}
+}
+
+func TestUnmarshalToCallbackContext(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count := 0
+	err := UnmarshalToCallbackContext(ctx, &endlessReader{}, func(s parallelSample) {
+		count++
+		if count == 3 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 calls, got %d", count)
+	}
+
+	// a callback error stops the reading, without going through the rest of the input
+	stop := errors.New("stop")
+	err = UnmarshalToCallbackWithError(&endlessReader{}, func(s parallelSample) error {
+		if s.ID == 5 {
+			return stop
+		}
+		return nil
+	}, WithWorkers(2))
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected the callback error, got %v", err)
+	}
+	checkGoroutines(t, before)
+
+	err = UnmarshalToCallbackContext(ctx, strings.NewReader("id,name\n1,a"), func(parallelSample) {})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTypedDecoderNext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d, err := NewDecoder[parallelSample](strings.NewReader("id,name\n1,a\n2,b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := d.Next(ctx)
+	if err != nil || s.ID != 1 {
+		t.Fatalf("expected sample 1, got %+v, %v", s, err)
+	}
+	cancel()
+	if _, err := d.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := d.Next(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Next(context.Background()); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestTypedDecoderNextCancelledDuringRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := &cancelReader{Reader: &endlessReader{}, cancel: cancel}
+	d, err := NewDecoder[parallelSample](in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in.armed = true
+	if s, err := d.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %+v, %v", s, err)
+	}
+}
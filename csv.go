@@ -8,6 +8,7 @@ package gocsv
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
@@ -15,7 +16,6 @@ import (
 	"os"
 	"reflect"
 	"strings"
-	"sync"
 )
 
 // FailIfUnmatchedStructTags indicates whether it is considered an error when there is an unmatched
@@ -39,6 +39,9 @@ var TagSeparator = ","
 // FieldSeperator defines how to combine parent struct with child struct
 var FieldsCombiner = "."
 
+// NullTokens defines the cell values standing for a null value (cf. Config.NullTokens)
+var NullTokens []string
+
 // Normalizer is a function that takes and returns a string. It is applied to
 // struct and header field values before they are compared. It can be used to alter
 // names for comparison. For instance, you could allow case insensitive matching
@@ -47,31 +50,37 @@ type Normalizer func(string) string
 
 type ErrorHandler func(*csv.ParseError) bool
 
-// normalizeName function initially set to a nop Normalizer.
-var normalizeName = DefaultNameNormalizer()
+// normalizeName is the default header normalizer, nil meaning names are compared as is.
+var normalizeName Normalizer
 
 // DefaultNameNormalizer is a nop Normalizer.
 func DefaultNameNormalizer() Normalizer { return func(s string) string { return s } }
 
-// SetHeaderNormalizer sets the normalizer used to normalize struct and header field names.
+// SetHeaderNormalizer sets the normalizer used by default to normalize struct and header field names.
+// Use WithHeaderNormalizer to set it for a single call.
 func SetHeaderNormalizer(f Normalizer) {
 	normalizeName = f
-	// Need to clear the cache hen the header normalizer changes.
-	structInfoCache = sync.Map{}
 }
 
 // --------------------------------------------------------------------------
 // CSVWriter used to format CSV
 
-var selfCSVWriter = DefaultCSVWriter
+// selfCSVWriter is nil until SetCSVWriter is called, so that the default
+// writer follows the TagSeparator of each call's config.
+var selfCSVWriter func(io.Writer) *SafeCSVWriter
 
 // DefaultCSVWriter is the default SafeCSVWriter used to format CSV (cf. csv.NewWriter)
 func DefaultCSVWriter(out io.Writer) *SafeCSVWriter {
+	return newCSVWriter(out, TagSeparator)
+}
+
+func newCSVWriter(out io.Writer, tagSeparator string) *SafeCSVWriter {
 	writer := NewSafeCSVWriter(csv.NewWriter(out))
+	writer.out = out
 
 	// As only one rune can be defined as a CSV separator, we are going to trim
 	// the custom tag separator and use the first rune.
-	if runes := []rune(strings.TrimSpace(TagSeparator)); len(runes) > 0 {
+	if runes := []rune(strings.TrimSpace(tagSeparator)); len(runes) > 0 {
 		writer.Comma = runes[0]
 	}
 
@@ -79,14 +88,11 @@ func DefaultCSVWriter(out io.Writer) *SafeCSVWriter {
 }
 
 // SetCSVWriter sets the SafeCSVWriter used to format CSV.
+// Use WithCSVWriter to set it for a single call.
 func SetCSVWriter(csvWriter func(io.Writer) *SafeCSVWriter) {
 	selfCSVWriter = csvWriter
 }
 
-func getCSVWriter(out io.Writer) *SafeCSVWriter {
-	return selfCSVWriter(out)
-}
-
 // --------------------------------------------------------------------------
 // CSVReader used to parse CSV
 
@@ -106,141 +112,166 @@ func LazyCSVReader(in io.Reader) CSVReader {
 }
 
 // SetCSVReader sets the CSV reader used to parse CSV.
+// Use WithCSVReader to set it for a single call.
 func SetCSVReader(csvReader func(io.Reader) CSVReader) {
 	selfCSVReader = csvReader
 }
 
-func getCSVReader(in io.Reader) CSVReader {
-	return selfCSVReader(in)
-}
-
 // --------------------------------------------------------------------------
 // Marshal functions
 
 // MarshalFile saves the interface as CSV in the file.
-func MarshalFile(in interface{}, file *os.File) (err error) {
-	return Marshal(in, file)
+func MarshalFile(in interface{}, file *os.File, opts ...Option) (err error) {
+	return Marshal(in, file, opts...)
 }
 
 // MarshalString returns the CSV string from the interface.
-func MarshalString(in interface{}) (out string, err error) {
+func MarshalString(in interface{}, opts ...Option) (out string, err error) {
 	bufferString := bytes.NewBufferString(out)
-	if err := Marshal(in, bufferString); err != nil {
+	if err := Marshal(in, bufferString, opts...); err != nil {
 		return "", err
 	}
 	return bufferString.String(), nil
 }
 
 // MarshalStringWithoutHeaders returns the CSV string from the interface.
-func MarshalStringWithoutHeaders(in interface{}) (out string, err error) {
+func MarshalStringWithoutHeaders(in interface{}, opts ...Option) (out string, err error) {
 	bufferString := bytes.NewBufferString(out)
-	if err := MarshalWithoutHeaders(in, bufferString); err != nil {
+	if err := MarshalWithoutHeaders(in, bufferString, opts...); err != nil {
 		return "", err
 	}
 	return bufferString.String(), nil
 }
 
 // MarshalBytes returns the CSV bytes from the interface.
-func MarshalBytes(in interface{}) (out []byte, err error) {
+func MarshalBytes(in interface{}, opts ...Option) (out []byte, err error) {
 	bufferString := bytes.NewBuffer(out)
-	if err := Marshal(in, bufferString); err != nil {
+	if err := Marshal(in, bufferString, opts...); err != nil {
 		return nil, err
 	}
 	return bufferString.Bytes(), nil
 }
 
 // Marshal returns the CSV in writer from the interface.
-func Marshal(in interface{}, out io.Writer) (err error) {
-	writer := getCSVWriter(out)
-	return writeTo(writer, in, false)
+func Marshal(in interface{}, out io.Writer, opts ...Option) (err error) {
+	cfg := newConfig(opts)
+	return writeTo(cfg, cfg.getCSVWriter(out), in, false)
 }
 
 // MarshalWithoutHeaders returns the CSV in writer from the interface.
-func MarshalWithoutHeaders(in interface{}, out io.Writer) (err error) {
-	writer := getCSVWriter(out)
-	return writeTo(writer, in, true)
+func MarshalWithoutHeaders(in interface{}, out io.Writer, opts ...Option) (err error) {
+	cfg := newConfig(opts)
+	return writeTo(cfg, cfg.getCSVWriter(out), in, true)
 }
 
 // MarshalChan returns the CSV read from the channel.
-func MarshalChan(c <-chan interface{}, out CSVWriter) error {
-	return writeFromChan(out, c, false)
+func MarshalChan(c <-chan interface{}, out CSVWriter, opts ...Option) error {
+	return writeFromChan(newConfig(opts), out, c, false)
 }
 
 // MarshalChanWithoutHeaders returns the CSV read from the channel.
-func MarshalChanWithoutHeaders(c <-chan interface{}, out CSVWriter) error {
-	return writeFromChan(out, c, true)
+func MarshalChanWithoutHeaders(c <-chan interface{}, out CSVWriter, opts ...Option) error {
+	return writeFromChan(newConfig(opts), out, c, true)
 }
 
 // MarshalCSV returns the CSV in writer from the interface.
-func MarshalCSV(in interface{}, out CSVWriter) (err error) {
-	return writeTo(out, in, false)
+func MarshalCSV(in interface{}, out CSVWriter, opts ...Option) (err error) {
+	return writeTo(newConfig(opts), out, in, false)
 }
 
 // MarshalCSVWithoutHeaders returns the CSV in writer from the interface.
-func MarshalCSVWithoutHeaders(in interface{}, out CSVWriter) (err error) {
-	return writeTo(out, in, true)
+func MarshalCSVWithoutHeaders(in interface{}, out CSVWriter, opts ...Option) (err error) {
+	return writeTo(newConfig(opts), out, in, true)
 }
 
 // --------------------------------------------------------------------------
 // Unmarshal functions
 
 // UnmarshalFile parses the CSV from the file in the interface.
-func UnmarshalFile(in *os.File, out interface{}) error {
-	return Unmarshal(in, out)
+func UnmarshalFile(in *os.File, out interface{}, opts ...Option) error {
+	return Unmarshal(in, out, opts...)
 }
 
 // UnmarshalMultipartFile parses the CSV from the multipart file in the interface.
-func UnmarshalMultipartFile(in *multipart.File, out interface{}) error {
-	return Unmarshal(convertTo(in), out)
+func UnmarshalMultipartFile(in *multipart.File, out interface{}, opts ...Option) error {
+	return Unmarshal(convertTo(in), out, opts...)
 }
 
 // UnmarshalFileWithErrorHandler parses the CSV from the file in the interface.
-func UnmarshalFileWithErrorHandler(in *os.File, errHandler ErrorHandler, out interface{}) error {
-	return UnmarshalWithErrorHandler(in, errHandler, out)
+func UnmarshalFileWithErrorHandler(in *os.File, errHandler ErrorHandler, out interface{}, opts ...Option) error {
+	return UnmarshalWithErrorHandler(in, errHandler, out, opts...)
 }
 
 // UnmarshalString parses the CSV from the string in the interface.
-func UnmarshalString(in string, out interface{}) error {
-	return Unmarshal(strings.NewReader(in), out)
+func UnmarshalString(in string, out interface{}, opts ...Option) error {
+	return Unmarshal(strings.NewReader(in), out, opts...)
 }
 
 // UnmarshalBytes parses the CSV from the bytes in the interface.
-func UnmarshalBytes(in []byte, out interface{}) error {
-	return Unmarshal(bytes.NewReader(in), out)
+func UnmarshalBytes(in []byte, out interface{}, opts ...Option) error {
+	return Unmarshal(bytes.NewReader(in), out, opts...)
 }
 
 // Unmarshal parses the CSV from the reader in the interface.
-func Unmarshal(in io.Reader, out interface{}) error {
-	return readTo(newSimpleDecoderFromReader(in), out)
+// Records are read and decoded one at a time, so besides the decoded slice
+// itself, the memory used is bounded by a single CSV record.
+func Unmarshal(in io.Reader, out interface{}, opts ...Option) error {
+	cfg := newConfig(opts)
+	return readTo(cfg, newSimpleDecoderFromReader(cfg, in), out)
 }
 
 // Unmarshal parses the CSV from the reader in the interface.
-func UnmarshalWithErrorHandler(in io.Reader, errHandle ErrorHandler, out interface{}) error {
-	return readToWithErrorHandler(newSimpleDecoderFromReader(in), errHandle, out)
+func UnmarshalWithErrorHandler(in io.Reader, errHandle ErrorHandler, out interface{}, opts ...Option) error {
+	cfg := newConfig(opts)
+	return readToWithErrorHandler(cfg, newSimpleDecoderFromReader(cfg, in), errHandle, out)
+}
+
+// UnmarshalWithHeaders parses the CSV from the reader in the interface, its
+// columns being named by headers: the first row is a record, not a header.
+// The names are matched against the struct tags as if they had been read from
+// the input.
+func UnmarshalWithHeaders(in io.Reader, headers []string, out interface{}, opts ...Option) error {
+	if len(headers) == 0 {
+		return errNoHeaders
+	}
+	cfg := newConfig(opts)
+	return readToWithHeaders(cfg, newSimpleDecoderFromReader(cfg, in), headers, nil, out)
+}
+
+// UnmarshalCSVWithHeaders parses the CSV with passed in CSV reader, its
+// columns being named by headers (see UnmarshalWithHeaders).
+func UnmarshalCSVWithHeaders(in CSVReader, headers []string, out interface{}, opts ...Option) error {
+	if len(headers) == 0 {
+		return errNoHeaders
+	}
+	return readToWithHeaders(newConfig(opts), csvDecoder{in}, headers, nil, out)
 }
 
 // UnmarshalWithoutHeaders parses the CSV from the reader in the interface.
-func UnmarshalWithoutHeaders(in io.Reader, out interface{}) error {
-	return readToWithoutHeaders(newSimpleDecoderFromReader(in), out)
+func UnmarshalWithoutHeaders(in io.Reader, out interface{}, opts ...Option) error {
+	cfg := newConfig(opts)
+	return readToWithoutHeaders(cfg, newSimpleDecoderFromReader(cfg, in), out)
 }
 
 // UnmarshalCSVWithoutHeaders parses a headerless CSV with passed in CSV reader
-func UnmarshalCSVWithoutHeaders(in CSVReader, out interface{}) error {
-	return readToWithoutHeaders(csvDecoder{in}, out)
+func UnmarshalCSVWithoutHeaders(in CSVReader, out interface{}, opts ...Option) error {
+	return readToWithoutHeaders(newConfig(opts), csvDecoder{in}, out)
 }
 
 // UnmarshalDecoder parses the CSV from the decoder in the interface
-func UnmarshalDecoder(in Decoder, out interface{}) error {
-	return readTo(in, out)
+func UnmarshalDecoder(in Decoder, out interface{}, opts ...Option) error {
+	return readTo(newConfig(opts), in, out)
 }
 
 // UnmarshalCSV parses the CSV from the reader in the interface.
-func UnmarshalCSV(in CSVReader, out interface{}) error {
-	return readTo(csvDecoder{in}, out)
+func UnmarshalCSV(in CSVReader, out interface{}, opts ...Option) error {
+	return readTo(newConfig(opts), csvDecoder{in}, out)
 }
 
-// UnmarshalCSVToMap parses a CSV of 2 columns into a map.
-func UnmarshalCSVToMap(in CSVReader, out interface{}) error {
+// UnmarshalCSVToMap parses a CSV of 2 columns into a map. The values found
+// in Config.NullTokens are set to the zero value.
+func UnmarshalCSVToMap(in CSVReader, out interface{}, opts ...Option) error {
+	cfg := newConfig(opts)
 	decoder := NewSimpleDecoderFromCSVReader(in)
 	header, err := decoder.GetCSVRow()
 	if err != nil {
@@ -265,10 +296,10 @@ func UnmarshalCSVToMap(in CSVReader, out interface{}) error {
 		} else if err != nil {
 			return err
 		}
-		if err := setField(key, line[0], false); err != nil {
+		if err := setField(key, line[0], &fieldInfo{}); err != nil {
 			return err
 		}
-		if err := setField(value, line[1], false); err != nil {
+		if err := setField(value.Elem(), line[1], &fieldInfo{nullTokens: cfg.NullTokens}); err != nil {
 			return err
 		}
 		outValue.SetMapIndex(key.Elem(), value.Elem())
@@ -278,210 +309,236 @@ func UnmarshalCSVToMap(in CSVReader, out interface{}) error {
 
 // UnmarshalToChan parses the CSV from the reader and send each value in the chan c.
 // The channel must have a concrete type.
-func UnmarshalToChan(in io.Reader, c interface{}) error {
+func UnmarshalToChan(in io.Reader, c interface{}, opts ...Option) error {
+	if c == nil {
+		return fmt.Errorf("goscv: channel is %v", c)
+	}
+	cfg := newConfig(opts)
+	return readEach(cfg, newSimpleDecoderFromReader(cfg, in), nil, c)
+}
+
+// UnmarshalToChanContext is like UnmarshalToChan, but stops reading once ctx
+// is done. The channel is then closed and ctx.Err() is returned.
+func UnmarshalToChanContext(ctx context.Context, in io.Reader, c interface{}, opts ...Option) error {
 	if c == nil {
 		return fmt.Errorf("goscv: channel is %v", c)
 	}
-	return readEach(newSimpleDecoderFromReader(in), nil, c)
+	cfg := newConfig(opts)
+	return readEachContext(ctx, cfg, newSimpleDecoderFromReader(cfg, in), nil, nil, c)
 }
 
 // UnmarshalToChanWithErrorHandler parses the CSV from the reader in the interface.
-func UnmarshalToChanWithErrorHandler(in io.Reader, errorHandler ErrorHandler, c interface{}) error {
+func UnmarshalToChanWithErrorHandler(in io.Reader, errorHandler ErrorHandler, c interface{}, opts ...Option) error {
 	if c == nil {
 		return fmt.Errorf("goscv: channel is %v", c)
 	}
-	return readEach(newSimpleDecoderFromReader(in), errorHandler, c)
+	cfg := newConfig(opts)
+	return readEach(cfg, newSimpleDecoderFromReader(cfg, in), errorHandler, c)
+}
+
+// UnmarshalToChanWithHeaders parses the CSV from the reader and send each value in the chan c,
+// its columns being named by headers (see UnmarshalWithHeaders).
+// The channel must have a concrete type.
+func UnmarshalToChanWithHeaders(in io.Reader, headers []string, c interface{}, opts ...Option) error {
+	if c == nil {
+		return fmt.Errorf("goscv: channel is %v", c)
+	}
+	if len(headers) == 0 {
+		return errNoHeaders
+	}
+	cfg := newConfig(opts)
+	return readEachContext(context.Background(), cfg, newSimpleDecoderFromReader(cfg, in), headers, nil, c)
 }
 
 // UnmarshalToChanWithoutHeaders parses the CSV from the reader and send each value in the chan c.
 // The channel must have a concrete type.
-func UnmarshalToChanWithoutHeaders(in io.Reader, c interface{}) error {
+func UnmarshalToChanWithoutHeaders(in io.Reader, c interface{}, opts ...Option) error {
 	if c == nil {
 		return fmt.Errorf("goscv: channel is %v", c)
 	}
-	return readEachWithoutHeaders(newSimpleDecoderFromReader(in), c)
+	cfg := newConfig(opts)
+	return readEachWithoutHeaders(cfg, newSimpleDecoderFromReader(cfg, in), c)
 }
 
 // UnmarshalDecoderToChan parses the CSV from the decoder and send each value in the chan c.
 // The channel must have a concrete type.
-func UnmarshalDecoderToChan(in SimpleDecoder, c interface{}) error {
+func UnmarshalDecoderToChan(in SimpleDecoder, c interface{}, opts ...Option) error {
 	if c == nil {
 		return fmt.Errorf("goscv: channel is %v", c)
 	}
-	return readEach(in, nil, c)
+	return readEach(newConfig(opts), in, nil, c)
 }
 
 // UnmarshalStringToChan parses the CSV from the string and send each value in the chan c.
 // The channel must have a concrete type.
-func UnmarshalStringToChan(in string, c interface{}) error {
-	return UnmarshalToChan(strings.NewReader(in), c)
+func UnmarshalStringToChan(in string, c interface{}, opts ...Option) error {
+	return UnmarshalToChan(strings.NewReader(in), c, opts...)
 }
 
 // UnmarshalBytesToChan parses the CSV from the bytes and send each value in the chan c.
 // The channel must have a concrete type.
-func UnmarshalBytesToChan(in []byte, c interface{}) error {
-	return UnmarshalToChan(bytes.NewReader(in), c)
+func UnmarshalBytesToChan(in []byte, c interface{}, opts ...Option) error {
+	return UnmarshalToChan(bytes.NewReader(in), c, opts...)
 }
 
 // UnmarshalToCallback parses the CSV from the reader and send each value to the given func f.
-// The func must look like func(Struct).
-func UnmarshalToCallback(in io.Reader, f interface{}) error {
-	valueFunc := reflect.ValueOf(f)
+// The func must look like func(Struct). If it returns an error, processing stops
+// and the error is returned.
+func UnmarshalToCallback(in io.Reader, f interface{}, opts ...Option) error {
+	return UnmarshalToCallbackContext(context.Background(), in, f, opts...)
+}
+
+// UnmarshalToCallbackContext parses the CSV from the reader and send each value
+// to the given func f, until ctx is done. It then returns ctx.Err().
+// The func must look like func(Struct) or func(Struct) error. If it returns an
+// error, processing stops and the error is returned.
+func UnmarshalToCallbackContext(ctx context.Context, in io.Reader, f interface{}, opts ...Option) error {
+	elemType, call, err := getCallback(f, true)
+	if err != nil {
+		return err
+	}
+	cfg := newConfig(opts)
+	return readEachFunc(ctx, cfg, newSimpleDecoderFromReader(cfg, in), nil, nil, elemType, call)
+}
+
+// UnmarshalToCallbackWithHeaders parses the CSV from the reader and send each value to the given func f,
+// its columns being named by headers (see UnmarshalWithHeaders).
+// The func must look like func(Struct) or func(Struct) error. If it returns an
+// error, processing stops and the error is returned.
+func UnmarshalToCallbackWithHeaders(in io.Reader, headers []string, f interface{}, opts ...Option) error {
+	if len(headers) == 0 {
+		return errNoHeaders
+	}
+	elemType, call, err := getCallback(f, true)
+	if err != nil {
+		return err
+	}
+	cfg := newConfig(opts)
+	return readEachFunc(context.Background(), cfg, newSimpleDecoderFromReader(cfg, in), headers, nil, elemType, call)
+}
+
+// UnmarshalDecoderToCallback parses the CSV from the decoder and send each value to the given func f.
+// The func must look like func(Struct). The values it returns, if any, are
+// ignored: f is called for every record (see UnmarshalDecoderToCallbackWithError).
+func UnmarshalDecoderToCallback(in SimpleDecoder, f interface{}, opts ...Option) error {
+	elemType, call, err := getCallback(f, false)
+	if err != nil {
+		return err
+	}
+	return readEachFunc(context.Background(), newConfig(opts), in, nil, nil, elemType, call)
+}
+
+// UnmarshalDecoderToCallbackWithError parses the CSV from the decoder and
+// send each value to the given func f.
+//
+// If func returns error, it will stop processing and propagate
+// the error to caller.
+//
+// The func must look like func(Struct) error.
+func UnmarshalDecoderToCallbackWithError(in SimpleDecoder, f interface{}, opts ...Option) error {
+	if err := checkErrorCallback(f); err != nil {
+		return err
+	}
+	elemType, call, err := getCallback(f, true)
+	if err != nil {
+		return err
+	}
+	return readEachFunc(context.Background(), newConfig(opts), in, nil, nil, elemType, call)
+}
+
+// getCallback checks that f is a func with one parameter, and returns the type
+// of this parameter along with a func calling f. With stopOnError, the error
+// returned by f, if any, is returned by this func.
+func getCallback(f interface{}, stopOnError bool) (reflect.Type, func(reflect.Value) error, error) {
 	t := reflect.TypeOf(f)
+	if t == nil || t.Kind() != reflect.Func {
+		return nil, nil, fmt.Errorf("cannot use %v, only function supported", f)
+	}
 	if t.NumIn() != 1 {
-		return fmt.Errorf("the given function must have exactly one parameter")
+		return nil, nil, fmt.Errorf("the given function must have exactly one parameter")
 	}
-	cerr := make(chan error)
-	c := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, t.In(0)), 0)
-	go func() {
-		cerr <- UnmarshalToChan(in, c.Interface())
-	}()
-	for {
-		select {
-		case err := <-cerr:
-			return err
-		default:
-		}
-		v, notClosed := c.Recv()
-		if !notClosed || v.Interface() == nil {
-			break
-		}
+	valueFunc := reflect.ValueOf(f)
+	return t.In(0), func(v reflect.Value) error {
 		callResults := valueFunc.Call([]reflect.Value{v})
 		// if last returned value from Call() is an error, return it
-		if len(callResults) > 0 {
+		if stopOnError && len(callResults) > 0 {
 			if err, ok := callResults[len(callResults)-1].Interface().(error); ok {
 				return err
 			}
 		}
-	}
-	return <-cerr
+		return nil
+	}, nil
 }
 
-// UnmarshalDecoderToCallback parses the CSV from the decoder and send each value to the given func f.
-// The func must look like func(Struct).
-func UnmarshalDecoderToCallback(in SimpleDecoder, f interface{}) error {
-	valueFunc := reflect.ValueOf(f)
+// checkErrorCallback checks that f looks like func(Struct) error.
+func checkErrorCallback(f interface{}) error {
 	t := reflect.TypeOf(f)
+	if t == nil || t.Kind() != reflect.Func {
+		return fmt.Errorf("cannot use %v, only function supported", f)
+	}
 	if t.NumIn() != 1 {
 		return fmt.Errorf("the given function must have exactly one parameter")
 	}
-	cerr := make(chan error)
-	c := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, t.In(0)), 0)
-	go func() {
-		cerr <- UnmarshalDecoderToChan(in, c.Interface())
-	}()
-	for {
-		select {
-		case err := <-cerr:
-			return err
-		default:
-		}
-		v, notClosed := c.Recv()
-		if !notClosed || v.Interface() == nil {
-			break
-		}
-		valueFunc.Call([]reflect.Value{v})
+	if t.NumOut() != 1 {
+		return fmt.Errorf("the given function must have exactly one return value")
+	}
+	if !isErrorType(t.Out(0)) {
+		return fmt.Errorf("the given function must only return error")
 	}
-	return <-cerr
+	return nil
 }
 
 // UnmarshalBytesToCallback parses the CSV from the bytes and send each value to the given func f.
 // The func must look like func(Struct).
-func UnmarshalBytesToCallback(in []byte, f interface{}) error {
-	return UnmarshalToCallback(bytes.NewReader(in), f)
+func UnmarshalBytesToCallback(in []byte, f interface{}, opts ...Option) error {
+	return UnmarshalToCallback(bytes.NewReader(in), f, opts...)
 }
 
 // UnmarshalStringToCallback parses the CSV from the string and send each value to the given func f.
 // The func must look like func(Struct).
-func UnmarshalStringToCallback(in string, c interface{}) (err error) {
-	return UnmarshalToCallback(strings.NewReader(in), c)
+func UnmarshalStringToCallback(in string, c interface{}, opts ...Option) (err error) {
+	return UnmarshalToCallback(strings.NewReader(in), c, opts...)
 }
 
 // UnmarshalToCallbackWithError parses the CSV from the reader and
 // send each value to the given func f.
 //
-// If func returns error, it will stop processing, drain the
-// parser and propagate the error to caller.
+// If func returns error, it will stop processing and propagate
+// the error to caller.
 //
 // The func must look like func(Struct) error.
-func UnmarshalToCallbackWithError(in io.Reader, f interface{}) error {
-	valueFunc := reflect.ValueOf(f)
-	t := reflect.TypeOf(f)
-	if t.NumIn() != 1 {
-		return fmt.Errorf("the given function must have exactly one parameter")
-	}
-	if t.NumOut() != 1 {
-		return fmt.Errorf("the given function must have exactly one return value")
-	}
-	if !isErrorType(t.Out(0)) {
-		return fmt.Errorf("the given function must only return error")
-	}
-
-	cerr := make(chan error)
-	c := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, t.In(0)), 0)
-	go func() {
-		cerr <- UnmarshalToChan(in, c.Interface())
-	}()
-
-	var fErr error
-	for {
-		select {
-		case err := <-cerr:
-			if err != nil {
-				return err
-			}
-			return fErr
-		default:
-		}
-		v, notClosed := c.Recv()
-		if !notClosed || v.Interface() == nil {
-			if err := <-cerr; err != nil {
-				fErr = err
-			}
-			break
-		}
-
-		// callback f has already returned an error, stop processing but keep draining the chan c
-		if fErr != nil {
-			continue
-		}
-
-		results := valueFunc.Call([]reflect.Value{v})
-
-		// If the callback f returns an error, stores it and returns it in future.
-		errValue := results[0].Interface()
-		if errValue != nil {
-			fErr = errValue.(error)
-		}
+func UnmarshalToCallbackWithError(in io.Reader, f interface{}, opts ...Option) error {
+	if err := checkErrorCallback(f); err != nil {
+		return err
 	}
-	return fErr
+	return UnmarshalToCallback(in, f, opts...)
 }
 
 // UnmarshalBytesToCallbackWithError parses the CSV from the bytes and
 // send each value to the given func f.
 //
-// If func returns error, it will stop processing, drain the
-// parser and propagate the error to caller.
+// If func returns error, it will stop processing and propagate
+// the error to caller.
 //
 // The func must look like func(Struct) error.
-func UnmarshalBytesToCallbackWithError(in []byte, f interface{}) error {
-	return UnmarshalToCallbackWithError(bytes.NewReader(in), f)
+func UnmarshalBytesToCallbackWithError(in []byte, f interface{}, opts ...Option) error {
+	return UnmarshalToCallbackWithError(bytes.NewReader(in), f, opts...)
 }
 
 // UnmarshalStringToCallbackWithError parses the CSV from the string and
 // send each value to the given func f.
 //
-// If func returns error, it will stop processing, drain the
-// parser and propagate the error to caller.
+// If func returns error, it will stop processing and propagate
+// the error to caller.
 //
 // The func must look like func(Struct) error.
-func UnmarshalStringToCallbackWithError(in string, c interface{}) (err error) {
-	return UnmarshalToCallbackWithError(strings.NewReader(in), c)
+func UnmarshalStringToCallbackWithError(in string, c interface{}, opts ...Option) (err error) {
+	return UnmarshalToCallbackWithError(strings.NewReader(in), c, opts...)
 }
 
 // CSVToMap creates a simple map from a CSV of 2 columns.
-func CSVToMap(in io.Reader) (map[string]string, error) {
-	decoder := newSimpleDecoderFromReader(in)
+func CSVToMap(in io.Reader, opts ...Option) (map[string]string, error) {
+	decoder := newSimpleDecoderFromReader(newConfig(opts), in)
 	header, err := decoder.GetCSVRow()
 	if err != nil {
 		return nil, err
@@ -503,8 +560,8 @@ func CSVToMap(in io.Reader) (map[string]string, error) {
 }
 
 // CSVToMaps takes a reader and returns an array of dictionaries, using the header row as the keys
-func CSVToMaps(reader io.Reader) ([]map[string]string, error) {
-	r := getCSVReader(reader)
+func CSVToMaps(reader io.Reader, opts ...Option) ([]map[string]string, error) {
+	r := newConfig(opts).getCSVReader(reader)
 	rows := []map[string]string{}
 	var header []string
 	for {
@@ -529,8 +586,8 @@ func CSVToMaps(reader io.Reader) ([]map[string]string, error) {
 }
 
 // CSVToChanMaps parses the CSV from the reader and send a dictionary in the chan c, using the header row as the keys.
-func CSVToChanMaps(reader io.Reader, c chan<- map[string]string) error {
-	r := csv.NewReader(reader)
+func CSVToChanMaps(reader io.Reader, c chan<- map[string]string, opts ...Option) error {
+	r := newConfig(opts).getCSVReader(reader)
 	var header []string
 	for {
 		record, err := r.Read()
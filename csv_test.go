@@ -2,6 +2,7 @@ package gocsv
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -14,7 +15,7 @@ func TestUnmarshalToCallback_ReaderError(t *testing.T) {
 		t.Error("UnmarshalToCallback should return first reader error")
 	}
 
-	err = UnmarshalDecoderToCallback(newSimpleDecoderFromReader(reader), func(Dummy) {})
+	err = UnmarshalDecoderToCallback(newSimpleDecoderFromReader(newConfig(nil), reader), func(Dummy) {})
 	if !errors.Is(err, readerErr) {
 		t.Error("UnmarshalDecoderToCallback should return first reader error")
 	}
@@ -25,6 +26,33 @@ func TestUnmarshalToCallback_ReaderError(t *testing.T) {
 	}
 }
 
+func TestUnmarshalDecoderToCallback_CallbackError(t *testing.T) {
+	in := "id,name\n1,a\n2,b\n3,c"
+	stop := errors.New("stop")
+
+	// the values returned by the callback are ignored, as they always were
+	count := 0
+	err := UnmarshalDecoderToCallback(newSimpleDecoderFromReader(newConfig(nil), strings.NewReader(in)), func(parallelSample) error {
+		count++
+		return stop
+	})
+	if err != nil || count != 3 {
+		t.Fatalf("expected 3 calls and no error, got %d calls and %v", count, err)
+	}
+
+	count = 0
+	err = UnmarshalDecoderToCallbackWithError(newSimpleDecoderFromReader(newConfig(nil), strings.NewReader(in)), func(parallelSample) error {
+		count++
+		return stop
+	})
+	if !errors.Is(err, stop) || count != 1 {
+		t.Fatalf("expected the callback error after 1 call, got %d calls and %v", count, err)
+	}
+	if err := UnmarshalDecoderToCallbackWithError(newSimpleDecoderFromReader(newConfig(nil), strings.NewReader(in)), func(parallelSample) {}); err == nil {
+		t.Fatal("expected an error for a callback without error result")
+	}
+}
+
 type errorReader struct{}
 
 func (e *errorReader) Read([]byte) (n int, err error) {
@@ -1,6 +1,7 @@
 package gocsv
 
 import (
+	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
@@ -12,6 +13,8 @@ import (
 var (
 	ErrUnmatchedStructTags = errors.New("unmatched struct tags")
 	ErrDoubleHeaderNames   = errors.New("double header names")
+
+	errNoHeaders = errors.New("no headers given")
 )
 
 // Decoder .
@@ -34,8 +37,18 @@ type csvDecoder struct {
 	CSVReader
 }
 
-func newSimpleDecoderFromReader(r io.Reader) SimpleDecoder {
-	return csvDecoder{getCSVReader(r)}
+func newSimpleDecoderFromReader(cfg *Config, r io.Reader) SimpleDecoder {
+	r = cfg.decodeInput(r)
+	var decoder SimpleDecoder
+	if cfg.hasPreamble() {
+		decoder = newPreambleDecoder(cfg, r)
+	} else {
+		decoder = csvDecoder{cfg.getCSVReader(r)}
+	}
+	if cfg.hasTrailer() {
+		decoder = newTrailerDecoder(cfg, decoder)
+	}
+	return decoder
 }
 
 var (
@@ -127,10 +140,10 @@ func maybeDoubleHeaderNames(headers []string) error {
 }
 
 // apply normalizer func to headers
-func normalizeHeaders(headers []string) []string {
+func normalizeHeaders(cfg *Config, headers []string) []string {
 	out := make([]string, len(headers))
 	for i, h := range headers {
-		out[i] = normalizeName(h)
+		out[i] = cfg.normalize(h)
 	}
 	return out
 }
@@ -140,220 +153,388 @@ func convertTo(file *multipart.File) io.Reader {
 	return io.Reader(*file)
 }
 
-func readTo(decoder Decoder, out interface{}) error {
-	return readToWithErrorHandler(decoder, nil, out)
+// readTo decodes the CSV from the decoder into the slice or array out.
+//
+// When the decoder is a SimpleDecoder, records are read and converted one at a
+// time and the slice grows as they arrive, so the memory held at any time is
+// bounded by one CSV record plus the decoded output, whatever the size of the
+// input. A Decoder only implementing GetCSVRows has all its records loaded first.
+func readTo(cfg *Config, decoder Decoder, out interface{}) error {
+	return readToWithErrorHandler(cfg, decoder, nil, out)
 }
 
-func readToWithErrorHandler(decoder Decoder, errHandler ErrorHandler, out interface{}) error {
+func readToWithErrorHandler(cfg *Config, decoder Decoder, errHandler ErrorHandler, out interface{}) error {
+	return readToWithHeaders(cfg, decoder, nil, errHandler, out)
+}
+
+// readToWithHeaders decodes the CSV from the decoder into the slice or array
+// out, its columns being named by headers. When headers is nil, they are read
+// from the first row of the decoder.
+func readToWithHeaders(cfg *Config, decoder Decoder, headers []string, errHandler ErrorHandler, out interface{}) error {
 	outValue, outType := getConcreteReflectValueAndType(out) // Get the concrete type (not pointer) (Slice<?> or Array<?>)
 	if err := ensureOutType(outType); err != nil {
 		return err
 	}
-	outInnerWasPointer, outInnerType := getConcreteContainerInnerType(outType) // Get the concrete inner type (not pointer) (Container<"?">)
+	_, outInnerType := getConcreteContainerInnerType(outType) // Get the concrete inner type (not pointer) (Container<"?">)
 	if err := ensureOutInnerType(outInnerType); err != nil {
 		return err
 	}
-	csvRows, err := decoder.GetCSVRows() // Get the CSV csvRows
+	if headers == nil {
+		detectHeaderFor(decoder, outInnerType)
+	}
+	nextRow, err := getRowReader(decoder)
 	if err != nil {
 		return err
 	}
-	if len(csvRows) == 0 {
-		return ErrEmptyCSVFile
+	firstLine := 1
+	if headers == nil {
+		headers, err = nextRow()
+		if err == io.EOF {
+			return ErrEmptyCSVFile
+		} else if err != nil {
+			return err
+		}
+		firstLine++ // account for the header
 	}
-	if err := ensureOutCapacity(&outValue, len(csvRows)); err != nil { // Ensure the container is big enough to hold the CSV content
+	firstLine += preambleLines(decoder) // account for the preamble
+	converter, err := newRowConverter(cfg, outType.Elem(), headers, errHandler)
+	if err != nil {
 		return err
 	}
-	outInnerStructInfo := getStructInfo(outInnerType) // Get the inner struct info to get CSV annotations
-	if len(outInnerStructInfo.Fields) == 0 {
-		return ErrNoStructTags
-	}
-
-	headers := normalizeHeaders(csvRows[0])
-	body := csvRows[1:]
-
-	csvHeadersLabels := make(map[int]*fieldInfo, len(outInnerStructInfo.Fields)) // Used to store the correspondance header <-> position in CSV
 
-	headerCount := map[string]int{}
-	for i, csvColumnHeader := range headers {
-		curHeaderCount := headerCount[csvColumnHeader]
-		if fieldInfo := getCSVFieldPosition(csvColumnHeader, outInnerStructInfo, curHeaderCount); fieldInfo != nil {
-			csvHeadersLabels[i] = fieldInfo
-			if ShouldAlignDuplicateHeadersWithStructFieldOrder {
-				curHeaderCount++
-				headerCount[csvColumnHeader] = curHeaderCount
-			}
-		}
+	store, flush := newOutStore(outValue)
+	err = convertRecords(cfg, converter, nextRow, firstLine, store)
+	flush()
+	if err != nil {
+		return err
 	}
+	return converter.collectedErrors()
+}
 
-	if FailIfUnmatchedStructTags {
-		if err := maybeMissingStructFields(outInnerStructInfo.Fields, headers); err != nil {
-			return err
-		}
+// getRowReader returns a function reading the CSV rows of the decoder one by
+// one, until io.EOF. Rows are streamed when the decoder is a SimpleDecoder.
+func getRowReader(decoder Decoder) (func() ([]string, error), error) {
+	if simpleDecoder, ok := decoder.(SimpleDecoder); ok {
+		return simpleDecoder.GetCSVRow, nil
 	}
-	if FailIfDoubleHeaderNames {
-		if err := maybeDoubleHeaderNames(headers); err != nil {
-			return err
-		}
+	csvRows, err := decoder.GetCSVRows()
+	if err != nil {
+		return nil, err
 	}
-
-	var withFieldsOK bool
-	var fieldTypeUnmarshallerWithKeys TypeUnmarshalCSVWithFields
-
-	for i, csvRow := range body {
-		objectIface := reflect.New(outValue.Index(i).Type()).Interface()
-		outInner := createNewOutInner(outInnerWasPointer, outInnerType)
-		for j, csvColumnContent := range csvRow {
-			if outInner.CanInterface() {
-				fieldTypeUnmarshallerWithKeys, withFieldsOK = objectIface.(TypeUnmarshalCSVWithFields)
-				if withFieldsOK {
-					if err := fieldTypeUnmarshallerWithKeys.UnmarshalCSVWithFields(headers[j], csvColumnContent); err != nil {
-						parseError := csv.ParseError{
-							Line:   i + 2, //add 2 to account for the header & 0-indexing of arrays
-							Column: j + 1,
-							Err:    err,
-						}
-						return &parseError
-					}
-					continue
-				}
-			}
-
-			if fieldInfo, ok := csvHeadersLabels[j]; ok { // Position found accordingly to header name
-				value := csvColumnContent
-				if value == "" {
-					value = fieldInfo.defaultValue
-				}
-				if err := setInnerField(&outInner, outInnerWasPointer, fieldInfo.IndexChain, value, fieldInfo.omitEmpty); err != nil { // Set field of struct
-					parseError := csv.ParseError{
-						Line:   i + 2, //add 2 to account for the header & 0-indexing of arrays
-						Column: j + 1,
-						Err:    err,
-					}
-					if errHandler == nil || !errHandler(&parseError) {
-						return &parseError
-					}
-				}
-			}
-		}
-
-		if withFieldsOK {
-			reflectedObject := reflect.ValueOf(objectIface)
-			outInner = reflectedObject.Elem()
+	return func() ([]string, error) {
+		if len(csvRows) == 0 {
+			return nil, io.EOF
 		}
+		csvRow := csvRows[0]
+		csvRows = csvRows[1:]
+		return csvRow, nil
+	}, nil
+}
 
-		outValue.Index(i).Set(outInner)
-	}
-	return nil
+func readEach(cfg *Config, decoder SimpleDecoder, errHandler ErrorHandler, c interface{}) error {
+	return readEachContext(context.Background(), cfg, decoder, nil, errHandler, c)
 }
 
-func readEach(decoder SimpleDecoder, errHandler ErrorHandler, c interface{}) error {
+// readEachContext decodes the CSV from the decoder and sends each value to the
+// channel c, which is closed on return. When ctx is done, reading stops and
+// ctx.Err() is returned. The columns are named by headers, or by the first row
+// of the decoder when nil.
+func readEachContext(ctx context.Context, cfg *Config, decoder SimpleDecoder, headers []string, errHandler ErrorHandler, c interface{}) error {
 	outValue, outType := getConcreteReflectValueAndType(c) // Get the concrete type (not pointer)
 	if outType.Kind() != reflect.Chan {
 		return fmt.Errorf("cannot use %v with type %s, only channel supported", c, outType)
 	}
 	defer outValue.Close()
+	return readEachFunc(ctx, cfg, decoder, headers, errHandler, outType.Elem(), func(outInner reflect.Value) error {
+		return sendContext(ctx, outValue, outInner)
+	})
+}
 
-	headers, err := decoder.GetCSVRow()
+// readEachFunc decodes the CSV from the decoder into values of elemType, and
+// gives each of them to store, in input order. When ctx is done, reading stops
+// and ctx.Err() is returned. The columns are named by headers, or by the first
+// row of the decoder when nil.
+func readEachFunc(ctx context.Context, cfg *Config, decoder SimpleDecoder, headers []string, errHandler ErrorHandler, elemType reflect.Type, store func(reflect.Value) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	firstLine := 1
+	if headers == nil {
+		detectHeaderFor(decoder, elemType)
+		var err error
+		if headers, err = decoder.GetCSVRow(); err != nil {
+			return err
+		}
+		firstLine++ // account for the header
+	}
+	firstLine += preambleLines(decoder) // account for the preamble
+	converter, err := newRowConverter(cfg, elemType, headers, errHandler)
 	if err != nil {
 		return err
 	}
-	headers = normalizeHeaders(headers)
 
-	outInnerWasPointer, outInnerType := getConcreteContainerInnerType(outType) // Get the concrete inner type (not pointer) (Container<"?">)
-	if err := ensureOutInnerType(outInnerType); err != nil {
+	nextRow := func() ([]string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return decoder.GetCSVRow()
+	}
+	if err := convertRecords(cfg, converter, nextRow, firstLine, func(_ int, outInner reflect.Value) error {
+		return store(outInner)
+	}); err != nil {
 		return err
 	}
-	outInnerStructInfo := getStructInfo(outInnerType) // Get the inner struct info to get CSV annotations
-	if len(outInnerStructInfo.Fields) == 0 {
-		return ErrNoStructTags
+	return converter.collectedErrors()
+}
+
+// sendContext sends v to the channel c, unless ctx is done first.
+func sendContext(ctx context.Context, c reflect.Value, v reflect.Value) error {
+	if ctx.Done() == nil { // the context can never be cancelled
+		c.Send(v)
+		return nil
 	}
-	csvHeadersLabels := make(map[int]*fieldInfo, len(outInnerStructInfo.Fields)) // Used to store the correspondance header <-> position in CSV
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: c, Send: v},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	})
+	if chosen == 1 {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// rowConverter converts CSV records into values of a container element type,
+// once the header row has been matched against the element's struct info.
+type rowConverter struct {
+	headers         []string // Normalized headers
+	rawHeaders      []string // Headers as read, naming the columns kept by the remain field
+	elemType        reflect.Type
+	innerWasPointer bool
+	innerType       reflect.Type
+	fieldInfos      map[int]*fieldInfo // Used to store the correspondance header <-> position in CSV
+	remain          *fieldInfo         // Field collecting the unmatched columns, if any
+	errHandler      ErrorHandler
+	collector       *errorCollector // Set in collect mode
+	failIfShort     bool            // See Config.FailIfShortRecords
+}
+
+// newRowConverter creates the converter of the records whose header row is
+// rawHeaders, as read, into values of elemType.
+func newRowConverter(cfg *Config, elemType reflect.Type, rawHeaders []string, errHandler ErrorHandler) (*rowConverter, error) {
+	headers := normalizeHeaders(cfg, rawHeaders)
+	innerWasPointer, innerType := false, elemType
+	if innerType.Kind() == reflect.Ptr {
+		innerWasPointer, innerType = true, innerType.Elem()
+	}
+	if err := ensureOutInnerType(innerType); err != nil {
+		return nil, err
+	}
+	innerStructInfo := getStructInfo(cfg, innerType) // Get the inner struct info to get CSV annotations
+	innerStructInfo = expandDynamicFieldsForHeaders(cfg, innerStructInfo, headers)
+	if len(innerStructInfo.Fields) == 0 {
+		return nil, ErrNoStructTags
+	}
+
+	fieldInfos := make(map[int]*fieldInfo, len(innerStructInfo.Fields))
 	headerCount := map[string]int{}
 	for i, csvColumnHeader := range headers {
 		curHeaderCount := headerCount[csvColumnHeader]
-		if fieldInfo := getCSVFieldPosition(csvColumnHeader, outInnerStructInfo, curHeaderCount); fieldInfo != nil {
-			csvHeadersLabels[i] = fieldInfo
-			if ShouldAlignDuplicateHeadersWithStructFieldOrder {
+		if fieldInfo := getCSVFieldPosition(csvColumnHeader, innerStructInfo, curHeaderCount); fieldInfo != nil {
+			fieldInfos[i] = fieldInfo
+			if cfg.ShouldAlignDuplicateHeadersWithStructFieldOrder {
 				curHeaderCount++
 				headerCount[csvColumnHeader] = curHeaderCount
 			}
 		}
 	}
-	if err := maybeMissingStructFields(outInnerStructInfo.Fields, headers); err != nil {
-		if FailIfUnmatchedStructTags {
-			return err
+
+	if cfg.FailIfUnmatchedStructTags {
+		if err := maybeMissingStructFields(innerStructInfo.Fields, headers); err != nil {
+			return nil, err
 		}
 	}
-	if FailIfDoubleHeaderNames {
+	if cfg.FailIfDoubleHeaderNames {
 		if err := maybeDoubleHeaderNames(headers); err != nil {
-			return err
+			return nil, err
 		}
 	}
+	if err := checkRequiredColumns(innerType, innerStructInfo.Fields, headers); err != nil {
+		return nil, err
+	}
 
-	var withFieldsOK bool
-	var fieldTypeUnmarshallerWithKeys TypeUnmarshalCSVWithFields
+	converter := &rowConverter{
+		headers:         headers,
+		rawHeaders:      rawHeaders,
+		elemType:        elemType,
+		innerWasPointer: innerWasPointer,
+		innerType:       innerType,
+		fieldInfos:      fieldInfos,
+		remain:          innerStructInfo.remain,
+		errHandler:      errHandler,
+		failIfShort:     cfg.FailIfShortRecords,
+	}
+	converter.collector = cfg.newErrorCollector()
+	return converter, nil
+}
 
-	i := 0
-	for {
-		objectIface := reflect.New(outValue.Type().Elem()).Interface()
-		line, err := decoder.GetCSVRow()
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return err
+// convert builds a new element from the CSV record found at the given line.
+//
+// In collect mode, the cells that cannot be decoded are recorded and left
+// unset, and convert only fails once more than Config.MaxErrors were recorded.
+// The recorded errors are then returned by collectedErrors.
+func (r *rowConverter) convert(record []string, line int) (reflect.Value, error) {
+	outInner, failures, err := r.decode(record, line)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if err := r.handleFailures(failures); err != nil {
+		return reflect.Value{}, err
+	}
+	return outInner, nil
+}
+
+// cellFailure is a cell that could not be decoded.
+type cellFailure struct {
+	parseError  *csv.ParseError
+	header      string
+	fieldInfo   *fieldInfo
+	value       string
+	skipHandler bool // the error handler is not called for UnmarshalCSVWithFields errors
+}
+
+// decode builds a new element from the CSV record found at the given line,
+// and returns the cells that could not be decoded. It does not modify the
+// converter, so that records can be decoded concurrently; the failures are
+// then handled in input order by handleFailures.
+func (r *rowConverter) decode(record []string, line int) (reflect.Value, []cellFailure, error) {
+	if r.failIfShort {
+		if err := checkShortRecord(record, r.headers, line); err != nil {
+			return reflect.Value{}, nil, err
 		}
-		outInner := createNewOutInner(outInnerWasPointer, outInnerType)
-		for j, csvColumnContent := range line {
-
-			if outInner.CanInterface() {
-				fieldTypeUnmarshallerWithKeys, withFieldsOK = objectIface.(TypeUnmarshalCSVWithFields)
-				if withFieldsOK {
-					if err := fieldTypeUnmarshallerWithKeys.UnmarshalCSVWithFields(headers[j], csvColumnContent); err != nil {
-						parseError := csv.ParseError{
-							Line:   i + 2, //add 2 to account for the header & 0-indexing of arrays
-							Column: j + 1,
-							Err:    err,
-						}
-						return &parseError
-					}
-
-					continue
-				}
-			}
+	}
+	objectIface := reflect.New(r.elemType).Interface()
+	if fieldTypeUnmarshallerWithKeys, ok := objectIface.(TypeUnmarshalCSVWithFields); ok {
+		failures := r.decodeWithFields(fieldTypeUnmarshallerWithKeys, record, line)
+		return reflect.ValueOf(objectIface).Elem(), failures, nil
+	}
 
-			if fieldInfo, ok := csvHeadersLabels[j]; ok { // Position found accordingly to header name
+	outInner := createNewOutInner(r.innerWasPointer, r.innerType)
+	failures, err := r.decodeFields(&outInner, record, line)
+	if err != nil {
+		return reflect.Value{}, nil, err
+	}
+	return outInner, failures, nil
+}
 
-				value := csvColumnContent
-				if value == "" {
-					value = fieldInfo.defaultValue
-				}
+// checkShortRecord reports the record found at the given line when it has
+// fewer fields than the header (see Config.FailIfShortRecords).
+func checkShortRecord(record []string, headers []string, line int) error {
+	if len(record) >= len(headers) {
+		return nil
+	}
+	return &csv.ParseError{
+		StartLine: line,
+		Line:      line,
+		Column:    len(record) + 1,
+		Err:       fmt.Errorf("record has %d fields, but the header has %d: %w", len(record), len(headers), csv.ErrFieldCount),
+	}
+}
 
-				if err := setInnerField(&outInner, outInnerWasPointer, fieldInfo.IndexChain, value, fieldInfo.omitEmpty); err != nil { // Set field of struct
-					parseError := &csv.ParseError{
-						Line:   i + 2, //add 2 to account for the header & 0-indexing of arrays
-						Column: j + 1,
-						Err:    err,
-					}
+// stopAtFailure tells whether the first failure stops the decoding of a
+// record, which is the case without error handler nor collect mode.
+func (r *rowConverter) stopAtFailure() bool {
+	return r.errHandler == nil && r.collector == nil
+}
 
-					if errHandler == nil || !errHandler(parseError) {
-						return parseError
-					}
-				}
+// decodeWithFields gives each cell of the record to the UnmarshalCSVWithFields
+// method of object, and returns the cells it failed to decode.
+func (r *rowConverter) decodeWithFields(object TypeUnmarshalCSVWithFields, record []string, line int) []cellFailure {
+	var failures []cellFailure
+	for j, csvColumnContent := range record {
+		if err := object.UnmarshalCSVWithFields(r.headers[j], csvColumnContent); err != nil {
+			parseError := &csv.ParseError{
+				Line:   line,
+				Column: j + 1,
+				Err:    err,
+			}
+			failures = append(failures, cellFailure{parseError, r.headers[j], nil, csvColumnContent, true})
+			if r.stopAtFailure() {
+				break
 			}
 		}
+	}
+	return failures
+}
 
-		if withFieldsOK {
-			reflectedObject := reflect.ValueOf(objectIface)
-			outInner = reflectedObject.Elem()
+// decodeFields sets the fields of outInner mapped to a column of the record,
+// and returns the cells that could not be decoded.
+func (r *rowConverter) decodeFields(outInner *reflect.Value, record []string, line int) ([]cellFailure, error) {
+	var failures []cellFailure
+	for j, csvColumnContent := range record {
+		if fieldInfo, ok := r.fieldInfos[j]; ok { // Position found accordingly to header name
+			value := csvColumnContent
+			if value == "" {
+				value = fieldInfo.defaultValue
+			}
+			err := setInnerField(outInner, r.innerWasPointer, fieldInfo.IndexChain, value, fieldInfo) // Set field of struct
+			if err == nil {
+				err = checkValidationRules(*outInner, r.innerType, fieldInfo, value, line, j+1)
+			}
+			if err != nil {
+				parseError := &csv.ParseError{
+					StartLine: line,
+					Line:      line,
+					Column:    j + 1,
+					Err:       err,
+				}
+				failures = append(failures, cellFailure{parseError, r.headers[j], fieldInfo, csvColumnContent, false})
+				if r.stopAtFailure() {
+					break
+				}
+			}
+		} else if r.remain != nil && j < len(r.headers) {
+			if err := setRemainField(*outInner, r.remain, r.rawHeaders[j], csvColumnContent); err != nil {
+				return nil, err
+			}
 		}
+	}
+	return failures, nil
+}
 
-		outValue.Send(outInner)
-		i++
+// handleFailures gives each failure to the error handler, then records the
+// ones it did not accept with collect. It returns the error that must stop
+// the decoding, if any.
+func (r *rowConverter) handleFailures(failures []cellFailure) error {
+	for _, failure := range failures {
+		if !failure.skipHandler && r.errHandler != nil && r.errHandler(failure.parseError) {
+			continue
+		}
+		if err := r.collect(failure.parseError, failure.header, failure.fieldInfo, failure.value); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func readEachWithoutHeaders(decoder SimpleDecoder, c interface{}) error {
+// collect records parseError in collect mode. It returns the error that must
+// stop the decoding: parseError itself when not collecting, or the collected
+// errors once there are too many of them.
+func (r *rowConverter) collect(parseError *csv.ParseError, header string, fieldInfo *fieldInfo, value string) error {
+	return r.collector.collect(parseError, header, r.innerType, fieldInfo, value)
+}
+
+// collectedErrors returns the errors recorded in collect mode as a *DecodeErrors, or nil.
+func (r *rowConverter) collectedErrors() error {
+	if r.collector == nil {
+		return nil
+	}
+	return r.collector.err()
+}
+
+func readEachWithoutHeaders(cfg *Config, decoder SimpleDecoder, c interface{}) error {
 	outValue, outType := getConcreteReflectValueAndType(c) // Get the concrete type (not pointer) (Slice<?> or Array<?>)
 	if err := ensureOutType(outType); err != nil {
 		return err
@@ -364,10 +545,15 @@ func readEachWithoutHeaders(decoder SimpleDecoder, c interface{}) error {
 	if err := ensureOutInnerType(outInnerType); err != nil {
 		return err
 	}
-	outInnerStructInfo := getStructInfo(outInnerType) // Get the inner struct info to get CSV annotations
+	outInnerStructInfo := getStructInfo(cfg, outInnerType) // Get the inner struct info to get CSV annotations
 	if len(outInnerStructInfo.Fields) == 0 {
 		return ErrNoStructTags
 	}
+	layout, err := newColumnLayout(cfg, outInnerType, outInnerStructInfo)
+	if err != nil {
+		return err
+	}
+	collector := cfg.newErrorCollector()
 
 	i := 0
 	for {
@@ -377,24 +563,17 @@ func readEachWithoutHeaders(decoder SimpleDecoder, c interface{}) error {
 		} else if err != nil {
 			return err
 		}
-		outInner := createNewOutInner(outInnerWasPointer, outInnerType)
-		for j, csvColumnContent := range line {
-			fieldInfo := outInnerStructInfo.Fields[j]
-			if err := setInnerField(&outInner, outInnerWasPointer, fieldInfo.IndexChain, csvColumnContent, fieldInfo.omitEmpty); err != nil { // Set field of struct
-				return &csv.ParseError{
-					Line:   i + 2, //add 2 to account for the header & 0-indexing of arrays
-					Column: j + 1,
-					Err:    err,
-				}
-			}
+		outInner, err := convertWithoutHeaders(line, i+1+preambleLines(decoder), outInnerWasPointer, layout, collector) // add 1 to account for the 0-indexing of arrays
+		if err != nil {
+			return err
 		}
 		outValue.Send(outInner)
 		i++
 	}
-	return nil
+	return collector.err()
 }
 
-func readToWithoutHeaders(decoder Decoder, out interface{}) error {
+func readToWithoutHeaders(cfg *Config, decoder Decoder, out interface{}) error {
 	outValue, outType := getConcreteReflectValueAndType(out) // Get the concrete type (not pointer) (Slice<?> or Array<?>)
 	if err := ensureOutType(outType); err != nil {
 		return err
@@ -403,37 +582,79 @@ func readToWithoutHeaders(decoder Decoder, out interface{}) error {
 	if err := ensureOutInnerType(outInnerType); err != nil {
 		return err
 	}
-	csvRows, err := decoder.GetCSVRows() // Get the CSV csvRows
+	nextRow, err := getRowReader(decoder)
 	if err != nil {
 		return err
 	}
-	if len(csvRows) == 0 {
-		return ErrEmptyCSVFile
-	}
-	if err := ensureOutCapacity(&outValue, len(csvRows)+1); err != nil { // Ensure the container is big enough to hold the CSV content
-		return err
-	}
-	outInnerStructInfo := getStructInfo(outInnerType) // Get the inner struct info to get CSV annotations
+	outInnerStructInfo := getStructInfo(cfg, outInnerType) // Get the inner struct info to get CSV annotations
 	if len(outInnerStructInfo.Fields) == 0 {
 		return ErrNoStructTags
 	}
+	layout, err := newColumnLayout(cfg, outInnerType, outInnerStructInfo)
+	if err != nil {
+		return err
+	}
+	collector := cfg.newErrorCollector()
+	store, flush := newOutStore(outValue)
+	defer flush()
 
-	for i, csvRow := range csvRows {
-		outInner := createNewOutInner(outInnerWasPointer, outInnerType)
-		for j, csvColumnContent := range csvRow {
-			fieldInfo := outInnerStructInfo.Fields[j]
-			if err := setInnerField(&outInner, outInnerWasPointer, fieldInfo.IndexChain, csvColumnContent, fieldInfo.omitEmpty); err != nil { // Set field of struct
-				return &csv.ParseError{
-					Line:   i + 1,
-					Column: j + 1,
-					Err:    err,
-				}
+	for i := 0; ; i++ {
+		csvRow, err := nextRow()
+		if err == io.EOF {
+			if i == 0 {
+				return ErrEmptyCSVFile
 			}
+			break
+		} else if err != nil {
+			return err
+		}
+		outInner, err := convertWithoutHeaders(csvRow, i+1+preambleLines(decoder), outInnerWasPointer, layout, collector)
+		if err != nil {
+			return err
+		}
+		if err := store(i, outInner); err != nil {
+			return err
 		}
-		outValue.Index(i).Set(outInner)
 	}
 
-	return nil
+	return collector.err()
+}
+
+// convertWithoutHeaders builds a new element from the CSV record found at the
+// given line, its columns being mapped to fields by the layout.
+//
+// In collect mode, i.e. with a collector, the cells that cannot be decoded are
+// recorded and left unset, as by rowConverter.convert.
+func convertWithoutHeaders(record []string, line int, outInnerWasPointer bool, layout *columnLayout, collector *errorCollector) (reflect.Value, error) {
+	if err := layout.checkLength(record, line); err != nil {
+		return reflect.Value{}, err
+	}
+	outInner := createNewOutInner(outInnerWasPointer, layout.outInnerType)
+	for j, csvColumnContent := range record {
+		if j >= len(layout.fields) {
+			break // Extra columns are skipped with column positions
+		}
+		fieldInfo := layout.fields[j]
+		if fieldInfo == nil { // Skipped column
+			continue
+		}
+		err := setInnerField(&outInner, outInnerWasPointer, fieldInfo.IndexChain, csvColumnContent, fieldInfo) // Set field of struct
+		if err == nil {
+			err = checkValidationRules(outInner, layout.outInnerType, fieldInfo, csvColumnContent, line, j+1)
+		}
+		if err != nil {
+			parseError := &csv.ParseError{
+				StartLine: line,
+				Line:      line,
+				Column:    j + 1,
+				Err:       err,
+			}
+			if err := collector.collect(parseError, "", layout.outInnerType, fieldInfo, csvColumnContent); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+	}
+	return outInner, nil
 }
 
 // Check if the outType is an array or a slice
@@ -458,19 +679,51 @@ func ensureOutInnerType(outInnerType reflect.Type) error {
 	return fmt.Errorf("cannot use " + outInnerType.String() + ", only struct supported")
 }
 
-func ensureOutCapacity(out *reflect.Value, csvLen int) error {
+// newOutStore returns the function storing the decoded values into the slice
+// or array out, and the function to call once the decoding is over. The values
+// of an array are kept aside and only copied by flush, unless there are more
+// than the array holds: a too small array is left untouched.
+func newOutStore(out reflect.Value) (store func(i int, outInner reflect.Value) error, flush func()) {
+	if out.Kind() != reflect.Array {
+		return func(i int, outInner reflect.Value) error {
+			return storeOutInner(out, i, outInner) // Grow the container when needed
+		}, func() {}
+	}
+	staged := reflect.MakeSlice(reflect.SliceOf(out.Type().Elem()), 0, out.Len())
+	tooSmall := false
+	store = func(i int, outInner reflect.Value) error {
+		if i >= out.Len() {
+			tooSmall = true
+			return storeOutInner(out, i, outInner) // Reports the array capacity problem
+		}
+		staged = reflect.Append(staged, outInner)
+		return nil
+	}
+	flush = func() {
+		if !tooSmall {
+			reflect.Copy(out, staged)
+		}
+	}
+	return store, flush
+}
+
+// storeOutInner stores outInner at index i of the slice or array out, growing
+// the slice when it is too short.
+func storeOutInner(out reflect.Value, i int, outInner reflect.Value) error {
+	if i < out.Len() {
+		out.Index(i).Set(outInner)
+		return nil
+	}
 	switch out.Kind() {
 	case reflect.Array:
-		if out.Len() < csvLen-1 { // Array is not big enough to hold the CSV content (arrays are not addressable)
-			return fmt.Errorf("array capacity problem: cannot store %d %s in %s", csvLen-1, out.Type().Elem().String(), out.Type().String())
-		}
+		// Array is not big enough to hold the CSV content (arrays are not addressable)
+		return fmt.Errorf("array capacity problem: cannot store more than %d %s in %s", out.Len(), out.Type().Elem().String(), out.Type().String())
 	case reflect.Slice:
-		if !out.CanAddr() && out.Len() < csvLen-1 { // Slice is not big enough tho hold the CSV content and is not addressable
+		if !out.CanAddr() { // Slice is not big enough tho hold the CSV content and is not addressable
 			return fmt.Errorf("slice capacity problem and is not addressable (did you forget &?)")
-		} else if out.CanAddr() && out.Len() < csvLen-1 {
-			out.Set(reflect.MakeSlice(out.Type(), csvLen-1, csvLen-1)) // Slice is not big enough, so grows it
 		}
 	}
+	out.Set(reflect.Append(out, outInner)) // Slice is not big enough, so grows it
 	return nil
 }
 
@@ -494,14 +747,12 @@ func createNewOutInner(outInnerWasPointer bool, outInnerType reflect.Type) refle
 	return reflect.New(outInnerType).Elem()
 }
 
-func setInnerField(outInner *reflect.Value, outInnerWasPointer bool, index []int, value string, omitEmpty bool) error {
+func setInnerField(outInner *reflect.Value, outInnerWasPointer bool, index []int, value string, fieldInfo *fieldInfo) error {
 	oi := *outInner
 	if outInnerWasPointer {
 		// initialize nil pointer
 		if oi.IsNil() {
-			if err := setField(oi, "", omitEmpty); err != nil {
-				return err
-			}
+			oi.Set(reflect.New(oi.Type().Elem()))
 		}
 		oi = outInner.Elem()
 	}
@@ -515,6 +766,9 @@ func setInnerField(outInner *reflect.Value, outInnerWasPointer bool, index []int
 			if newcap < 4 {
 				newcap = 4
 			}
+			if newcap < i+1 {
+				newcap = i + 1
+			}
 			newoi := reflect.MakeSlice(oi.Type(), oi.Len(), newcap)
 			reflect.Copy(newoi, oi)
 			oi.Set(newoi)
@@ -525,15 +779,15 @@ func setInnerField(outInner *reflect.Value, outInnerWasPointer bool, index []int
 
 		item := oi.Index(i)
 		if len(index) > 1 {
-			return setInnerField(&item, false, index[1:], value, omitEmpty)
+			return setInnerField(&item, false, index[1:], value, fieldInfo)
 		}
-		return setField(item, value, omitEmpty)
+		return setField(item, value, fieldInfo)
 	}
 
 	// because pointers can be nil need to recurse one index at a time and perform nil check
 	if len(index) > 1 {
 		nextField := oi.Field(index[0])
-		return setInnerField(&nextField, nextField.Kind() == reflect.Ptr, index[1:], value, omitEmpty)
+		return setInnerField(&nextField, nextField.Kind() == reflect.Ptr, index[1:], value, fieldInfo)
 	}
-	return setField(oi.FieldByIndex(index), value, omitEmpty)
+	return setField(oi.FieldByIndex(index), value, fieldInfo)
 }
@@ -19,10 +19,10 @@ func Test_readTo(t *testing.T) {
 	b := bytes.NewBufferString(`foo,BAR,Baz,Blah,SPtr,Omit
 f,1,baz,,*string,*string
 e,3,b,,,`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []Sample
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	if len(samples) != 2 {
@@ -42,9 +42,9 @@ e,3,b,,,`)
 	b = bytes.NewBufferString(`foo,BAR,Baz
 f,1,baz
 e,BAD_INPUT,b`)
-	d = newSimpleDecoderFromReader(b)
+	d = newSimpleDecoderFromReader(newConfig(nil), b)
 	samples = []Sample{}
-	err := readTo(d, &samples)
+	err := readTo(newConfig(nil), d, &samples)
 	if err == nil {
 		t.Fatalf("Expected error from bad input, got: %+v", samples)
 	}
@@ -74,10 +74,10 @@ func Test_readToNormalized(t *testing.T) {
 	b := bytes.NewBufferString(`FOO,BAR,BAZ,BLAH,SPTR,OMIT
 f,1,baz,,*string,*string
 e,3,b,,,`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []Sample
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	if len(samples) != 2 {
@@ -97,9 +97,9 @@ e,3,b,,,`)
 	b = bytes.NewBufferString(`foo,BAR,Baz
 f,1,baz
 e,BAD_INPUT,b`)
-	d = newSimpleDecoderFromReader(b)
+	d = newSimpleDecoderFromReader(newConfig(nil), b)
 	samples = []Sample{}
-	err := readTo(d, &samples)
+	err := readTo(newConfig(nil), d, &samples)
 	if err == nil {
 		t.Fatalf("Expected error from bad input, got: %+v", samples)
 	}
@@ -120,10 +120,10 @@ e,BAD_INPUT,b`)
 func Test_readTo_Time(t *testing.T) {
 	b := bytes.NewBufferString(`Foo
 1970-01-01T03:01:00+03:00`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []DateTime
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 
@@ -140,10 +140,10 @@ func Test_readTo_complex_embed(t *testing.T) {
 	b := bytes.NewBufferString(`first,foo,BAR,Baz,last,abc
 aa,bb,11,cc,dd,ee
 ff,gg,22,hh,ii,jj`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []SkipFieldSample
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	if len(samples) != 2 {
@@ -185,9 +185,9 @@ func Test_readTo_embed_ptr(t *testing.T) {
 	b := bytes.NewBufferString(`first,foo,BAR,Baz,last,abc
 aa,bb,11,cc,dd,ee
 ff,gg,22,hh,ii,jj`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 	var rows []EmbedPtrSample
-	if err := readTo(d, &rows); err != nil {
+	if err := readTo(newConfig(nil), d, &rows); err != nil {
 		t.Fatalf(err.Error())
 	}
 	expected := EmbedPtrSample{
@@ -212,7 +212,7 @@ func Test_readTo_slice(t *testing.T) {
 	reader.Comma = '\t'
 	d := csvDecoder{reader}
 	samples := []SliceSample{}
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	expected := SliceSample{Slice: []int{}}
@@ -228,10 +228,10 @@ func Test_readTo_slice(t *testing.T) {
 func Test_readTo_slice_structs(t *testing.T) {
 	b := bytes.NewBufferString(`s[0].string,slice[0].f,slice[1].s,s[1].float,a[0].s,array[0].float,a[1].s,array[1].float,ints[0],ints[1],ints[2]
 s1,1.1,s2,2.2,s3,3.3,s4,4.4,1,2,3`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []SliceStructSample
-	err := readTo(d, &samples)
+	err := readTo(newConfig(nil), d, &samples)
 	if err != nil {
 		t.Fatalf("failed to unmarshal: %v", err)
 	}
@@ -256,9 +256,9 @@ s1,1.1,s2,2.2,s3,3.3,s4,4.4,1,2,3`)
 func Test_readTo_embed_marshal(t *testing.T) {
 	b := bytes.NewBufferString(`foo
 bar`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 	var rows []EmbedMarshal
-	if err := readTo(d, &rows); err != nil {
+	if err := readTo(newConfig(nil), d, &rows); err != nil {
 		t.Fatalf(err.Error())
 	}
 	expected := EmbedMarshal{
@@ -272,9 +272,9 @@ bar`)
 func Test_readTo_embed_unmarshal_csv_with_clashing_field(t *testing.T) {
 	b := bytes.NewBufferString(`Symbol,Timestamp
 test,1656460798.693201614`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 	var rows []EmbedUnmarshalCSVWithClashingField
-	if err := readTo(d, &rows); err != nil {
+	if err := readTo(newConfig(nil), d, &rows); err != nil {
 		t.Fatalf(err.Error())
 	}
 	expected := EmbedUnmarshalCSVWithClashingField{
@@ -290,13 +290,13 @@ func Test_readEach(t *testing.T) {
 	b := bytes.NewBufferString(`first,foo,BAR,Baz,last,abc
 aa,bb,11,cc,dd,ee
 ff,gg,22,hh,ii,jj`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	c := make(chan SkipFieldSample)
 	e := make(chan error)
 	var samples []SkipFieldSample
 	go func() {
-		if err := readEach(d, nil, c); err != nil {
+		if err := readEach(newConfig(nil), d, nil, c); err != nil {
 			e <- err
 		}
 	}()
@@ -354,7 +354,7 @@ aa,bb,11,cc,dd,ee
 ff,gg,22,hh,ii,jj
 kk,ll,ab,mm,nn,oo
 `)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var errHandler ErrorHandler
 	errHandler = func(parseError *csv.ParseError) bool {
@@ -372,7 +372,7 @@ kk,ll,ab,mm,nn,oo
 	e := make(chan error)
 	var samples []SkipFieldSample
 	go func() {
-		if err := readEach(d, errHandler, c); err != nil {
+		if err := readEach(newConfig(nil), d, errHandler, c); err != nil {
 			e <- err
 		}
 	}()
@@ -429,13 +429,13 @@ func Test_readEachWithoutHeaders(t *testing.T) {
 	sptr := ""
 	b := bytes.NewBufferString(`f,1,baz,1.66,,,
 e,3,b,,,,`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	c := make(chan Sample)
 	e := make(chan error)
 	var samples []Sample
 	go func() {
-		if err := readEachWithoutHeaders(d, c); err != nil {
+		if err := readEachWithoutHeaders(newConfig(nil), d, c); err != nil {
 			e <- err
 		}
 	}()
@@ -472,13 +472,13 @@ func Test_readEach_TypeUnmarshalCSVWithFields(t *testing.T) {
 	b := bytes.NewBufferString(`foo,bar,baz,frop
 bb,1,cc,3.14
 gg,2,hh,4`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	c := make(chan UnmarshalCSVWithFieldsSample)
 	e := make(chan error)
 	var samples []UnmarshalCSVWithFieldsSample
 	go func() {
-		if err := readEach(d, nil, c); err != nil {
+		if err := readEach(newConfig(nil), d, nil, c); err != nil {
 			e <- err
 		}
 	}()
@@ -564,7 +564,7 @@ func Test_maybeDoubleHeaderNames(t *testing.T) {
 	b := bytes.NewBufferString(`foo,BAR,foo
 f,1,baz
 e,3,b`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 	var samples []Sample
 
 	// *** check maybeDoubleHeaderNames
@@ -575,7 +575,7 @@ e,3,b`)
 	}
 
 	// *** check readTo
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	// Double header allowed, value should be of third row
@@ -586,9 +586,9 @@ e,3,b`)
 	b = bytes.NewBufferString(`foo,BAR,foo
 f,1,baz
 e,3,b`)
-	d = newSimpleDecoderFromReader(b)
+	d = newSimpleDecoderFromReader(newConfig(nil), b)
 	ShouldAlignDuplicateHeadersWithStructFieldOrder = true
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	// Double header allowed, value should be of first row
@@ -599,7 +599,7 @@ e,3,b`)
 	ShouldAlignDuplicateHeadersWithStructFieldOrder = false
 	// Double header not allowed, should fail
 	FailIfDoubleHeaderNames = true
-	if err := readTo(d, &samples); err == nil {
+	if err := readTo(newConfig(nil), d, &samples); err == nil {
 		t.Fatal("Double header not allowed but no error raised. Function called is readTo.")
 	}
 
@@ -608,12 +608,12 @@ e,3,b`)
 	b = bytes.NewBufferString(`foo,BAR,foo
 f,1,baz
 e,3,b`)
-	d = newSimpleDecoderFromReader(b)
+	d = newSimpleDecoderFromReader(newConfig(nil), b)
 	samples = samples[:0]
 	c := make(chan Sample)
 	e := make(chan error)
 	go func() {
-		if err := readEach(d, nil, c); err != nil {
+		if err := readEach(newConfig(nil), d, nil, c); err != nil {
 			e <- err
 		}
 	}()
@@ -639,11 +639,11 @@ L1:
 	b = bytes.NewBufferString(`foo,BAR,foo
 f,1,baz
 e,3,b`)
-	d = newSimpleDecoderFromReader(b)
+	d = newSimpleDecoderFromReader(newConfig(nil), b)
 	c = make(chan Sample)
 	e = make(chan error)
 	go func() {
-		if err := readEach(d, nil, c); err == nil {
+		if err := readEach(newConfig(nil), d, nil, c); err == nil {
 			e <- err
 		}
 	}()
@@ -719,13 +719,13 @@ func TestRenamedTypesUnmarshal(t *testing.T) {
 	b := bytes.NewBufferString(`foo;bar
 1,4;1.5
 2,3;2.4`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 	var samples []RenamedSample
 
 	// Switch back to default for tests executed after this
 	defer SetCSVReader(DefaultCSVReader)
 
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	if samples[0].RenamedFloatUnmarshaler != 1.4 {
@@ -738,9 +738,9 @@ func TestRenamedTypesUnmarshal(t *testing.T) {
 	// Test that errors raised by UnmarshalCSV are correctly reported
 	b = bytes.NewBufferString(`foo;bar
 4.2;2.4`)
-	d = newSimpleDecoderFromReader(b)
+	d = newSimpleDecoderFromReader(newConfig(nil), b)
 	samples = samples[:0]
-	if perr, _ := readTo(d, &samples).(*csv.ParseError); perr == nil {
+	if perr, _ := readTo(newConfig(nil), d, &samples).(*csv.ParseError); perr == nil {
 		t.Fatalf("Expected ParseError, got nil.")
 	} else if _, ok := perr.Err.(UnmarshalError); !ok {
 		t.Fatalf("Expected UnmarshalError, got %v", perr.Err)
@@ -910,10 +910,10 @@ func (u *NoTagsSample) UnmarshalCSVWithFields(key, value string) error {
 func TestMultipleStructTags(t *testing.T) {
 	b := bytes.NewBufferString(`foo,BAR,Baz
 e,3,b`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []MultiTagSample
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	if samples[0].Foo != "b" {
@@ -922,9 +922,9 @@ e,3,b`)
 
 	b = bytes.NewBufferString(`foo,BAR
 e,3`)
-	d = newSimpleDecoderFromReader(b)
+	d = newSimpleDecoderFromReader(newConfig(nil), b)
 
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	if samples[0].Foo != "e" {
@@ -933,9 +933,9 @@ e,3`)
 
 	b = bytes.NewBufferString(`BAR,Baz
 3,b`)
-	d = newSimpleDecoderFromReader(b)
+	d = newSimpleDecoderFromReader(newConfig(nil), b)
 
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 	if samples[0].Foo != "b" {
@@ -946,7 +946,7 @@ e,3`)
 func TestStructTagSeparator(t *testing.T) {
 	b := bytes.NewBufferString(`foo,BAR,Baz
 e,3,b`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	defaultTagSeparator := TagSeparator
 	TagSeparator = "|"
@@ -955,7 +955,7 @@ e,3,b`)
 	}()
 
 	var samples []TagSeparatorSample
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 
@@ -967,7 +967,7 @@ e,3,b`)
 func TestCustomTag(t *testing.T) {
 	b := bytes.NewBufferString(`foo,BAR
 e,3`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	defaultTagName := TagName
 	TagName = "custom"
@@ -976,7 +976,7 @@ e,3`)
 	}()
 
 	var samples []CustomTagSample
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1152,10 +1152,10 @@ func TestUnmarshalWithoutHeader(t *testing.T) {
 	sptr := ""
 	b := bytes.NewBufferString(`f,1,baz,1.66,,,
 e,3,b,,,,`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []Sample
-	if err := readToWithoutHeaders(d, &samples); err != nil {
+	if err := readToWithoutHeaders(newConfig(nil), d, &samples); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1280,6 +1280,37 @@ func TestUnmarshalCSVToMap(t *testing.T) {
 	}
 }
 
+func TestUnmarshalCSVToMapNullTokens(t *testing.T) {
+	var sample map[string]*int
+	in := csv.NewReader(strings.NewReader("name,age\nBob,32\nAlice,NULL"))
+	if err := UnmarshalCSVToMap(in, &sample, WithNullTokens("NULL")); err != nil {
+		t.Fatal(err)
+	}
+	if len(sample) != 2 || *sample["Bob"] != 32 || sample["Alice"] != nil {
+		t.Fatalf("expected a nil age for Alice, got %v", sample)
+	}
+}
+
+func TestCSVToChanMapsWithOptions(t *testing.T) {
+	c := make(chan map[string]string)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- CSVToChanMaps(strings.NewReader("name;age\nBob;32\n"), c, WithDialect(Dialect{Delimiter: ';'}))
+		close(c)
+	}()
+	var maps []map[string]string
+	for m := range c {
+		maps = append(maps, m)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]string{{"name": "Bob", "age": "32"}}
+	if !reflect.DeepEqual(maps, expected) {
+		t.Fatalf("expected %v, got %v", expected, maps)
+	}
+}
+
 func BenchmarkCSVToMap(b *testing.B) {
 	bufstring := bytes.NewBufferString(`foo,BAR
 4,Jose
@@ -1312,10 +1343,10 @@ func BenchmarkUnmarshalCSVToMap(b *testing.B) {
 func Test_readTo_nested_struct(t *testing.T) {
 	b := bytes.NewBufferString(`one.boolField1,one.stringField2,two.boolField1,two.stringField2,three.boolField1,three.stringField2
 false,email_one,true,email_two,false,email_three`)
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []NestedSample
-	err := readTo(d, &samples)
+	err := readTo(newConfig(nil), d, &samples)
 	if err != nil {
 		t.Fatalf("failed to unmarshal: %v", err)
 	}
@@ -1347,10 +1378,10 @@ false,email_one,true,email_two,false,email_three`)
 
 func Test_readTo_inline_nested_struct(t *testing.T) {
 	b := bytes.NewBufferString("a,b,x\n1,2,3")
-	d := newSimpleDecoderFromReader(b)
+	d := newSimpleDecoderFromReader(newConfig(nil), b)
 
 	var samples []InlineFooSample
-	if err := readTo(d, &samples); err != nil {
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
 		t.Fatalf("failed to unmarshal: %v", err)
 	}
 
@@ -1359,3 +1390,82 @@ func Test_readTo_inline_nested_struct(t *testing.T) {
 		t.Fatalf("expected \n  sample: %v\n     got: %v", expected, samples)
 	}
 }
+
+// streamOnlyDecoder is a SimpleDecoder refusing to load all its rows at once.
+type streamOnlyDecoder struct {
+	SimpleDecoder
+}
+
+func (d streamOnlyDecoder) GetCSVRows() ([][]string, error) {
+	return nil, errors.New("GetCSVRows should not be called")
+}
+
+// rowsOnlyDecoder is a Decoder without GetCSVRow.
+type rowsOnlyDecoder struct {
+	rows [][]string
+}
+
+func (d rowsOnlyDecoder) GetCSVRows() ([][]string, error) {
+	return d.rows, nil
+}
+
+func Test_readTo_streaming(t *testing.T) {
+	d := streamOnlyDecoder{newSimpleDecoderFromReader(newConfig(nil), strings.NewReader(`foo,BAR
+f,1
+e,2
+g,3`))}
+
+	var samples []Sample
+	if err := readTo(newConfig(nil), d, &samples); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 || samples[2].Foo != "g" || samples[2].Bar != 3 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+
+	d = streamOnlyDecoder{newSimpleDecoderFromReader(newConfig(nil), strings.NewReader("x,5\nf,1"))}
+	if err := readToWithoutHeaders(newConfig(nil), d, &samples); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 || samples[0].Foo != "x" || samples[1].Foo != "f" || samples[2].Foo != "g" {
+		t.Fatalf("expected the existing elements to be overwritten in place, got %+v", samples)
+	}
+
+	var fromRows []Sample
+	if err := readTo(newConfig(nil), rowsOnlyDecoder{[][]string{{"foo"}, {"a"}, {"b"}}}, &fromRows); err != nil {
+		t.Fatal(err)
+	}
+	if len(fromRows) != 2 || fromRows[0].Foo != "a" || fromRows[1].Foo != "b" {
+		t.Fatalf("unexpected samples %+v", fromRows)
+	}
+
+	if err := readTo(newConfig(nil), rowsOnlyDecoder{}, &fromRows); err != ErrEmptyCSVFile {
+		t.Fatalf("expected ErrEmptyCSVFile, got %v", err)
+	}
+}
+
+func Test_readTo_array(t *testing.T) {
+	var samples [2]Sample
+	if err := readTo(newConfig(nil), newSimpleDecoderFromReader(newConfig(nil), strings.NewReader("foo\na\nb")), &samples); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Foo != "a" || samples[1].Foo != "b" {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+
+	err := readTo(newConfig(nil), newSimpleDecoderFromReader(newConfig(nil), strings.NewReader("foo\nc\nd\ne")), &samples)
+	if err == nil || !strings.Contains(err.Error(), "array capacity problem") {
+		t.Fatalf("expected an array capacity error, got %v", err)
+	}
+	if samples[0].Foo != "a" || samples[1].Foo != "b" {
+		t.Fatalf("expected a too small array to be left untouched, got %+v", samples)
+	}
+
+	err = UnmarshalWithoutHeaders(strings.NewReader("c,1\nd,2\ne,3"), &samples)
+	if err == nil || !strings.Contains(err.Error(), "array capacity problem") {
+		t.Fatalf("expected an array capacity error, got %v", err)
+	}
+	if samples[0].Foo != "a" || samples[1].Foo != "b" {
+		t.Fatalf("expected a too small array to be left untouched, got %+v", samples)
+	}
+}
@@ -0,0 +1,141 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// errInvalidDialect is returned when reading or writing with an invalid Dialect.
+var errInvalidDialect = errors.New("invalid dialect")
+
+// Dialect describes the format of a CSV file, independently of the struct tags
+// (see Config.TagSeparator). The zero value is RFC 4180 with "\n" line terminators.
+type Dialect struct {
+	// Delimiter separates the fields of a record, ',' when zero.
+	Delimiter rune
+
+	// Separator, when set, separates the fields instead of Delimiter, and may
+	// be several characters long, e.g. "||" or "~|~".
+	Separator string
+
+	// Quote encloses the fields holding delimiters, quotes or line breaks, '"'
+	// when zero.
+	Quote rune
+
+	// Escape escapes a Quote within a quoted field. When zero or equal to
+	// Quote, the quote is doubled, as in RFC 4180. Otherwise, e.g. with '\\',
+	// it escapes any character, in quoted fields or not, including itself.
+	Escape rune
+
+	// LineTerminator ends each record when writing: "\n" when empty, "\r\n"
+	// or "\r". "\n" and "\r\n" are accepted when reading, and "\r" too when
+	// it is the line terminator.
+	LineTerminator string
+
+	// Comment, when set, starts the lines ignored when reading.
+	Comment rune
+
+	// TrimLeadingSpace ignores the leading white space of the fields when reading.
+	TrimLeadingSpace bool
+
+	// LazyQuotes accepts quotes within unquoted fields, and bare quotes within
+	// quoted fields, when reading.
+	LazyQuotes bool
+}
+
+var (
+	// DialectRFC4180 is the format of RFC 4180, with "\r\n" line terminators.
+	DialectRFC4180 = Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\r\n"}
+
+	// DialectExcel is the format written by Excel, reading the stray quotes of
+	// hand-edited files.
+	DialectExcel = Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\r\n", LazyQuotes: true}
+
+	// DialectExcelSemicolon is the format written by Excel in the locales using
+	// the comma as decimal separator.
+	DialectExcelSemicolon = Dialect{Delimiter: ';', Quote: '"', LineTerminator: "\r\n", LazyQuotes: true}
+
+	// DialectTSV is the tab-separated format.
+	DialectTSV = Dialect{Delimiter: '\t', Quote: '"', LineTerminator: "\n"}
+
+	// DialectUnix is the comma-separated format with "\n" line terminators.
+	DialectUnix = Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\n"}
+)
+
+// separator returns the separator of the fields, Delimiter or ',' when unset.
+func (d Dialect) separator() string {
+	if d.Separator != "" {
+		return d.Separator
+	} else if d.Delimiter != 0 {
+		return string(d.Delimiter)
+	}
+	return ","
+}
+
+// quote returns the quote of the dialect, '"' when zero.
+func (d Dialect) quote() rune {
+	if d.Quote == 0 {
+		return '"'
+	}
+	return d.Quote
+}
+
+// validate returns an error when the dialect cannot be read back unambiguously.
+func (d Dialect) validate() error {
+	separator, quote := d.separator(), d.quote()
+	switch {
+	case !utf8.ValidString(separator) || !utf8.ValidRune(quote) || strings.ContainsAny(separator, "\r\n"):
+		return fmt.Errorf("%w: separator %q", errInvalidDialect, separator)
+	case quote == '\r' || quote == '\n' || strings.ContainsRune(separator, quote):
+		return fmt.Errorf("%w: quote %q", errInvalidDialect, quote)
+	case d.Escape == '\r' || d.Escape == '\n' || (d.Escape != 0 && strings.ContainsRune(separator, d.Escape)):
+		return fmt.Errorf("%w: escape %q", errInvalidDialect, d.Escape)
+	case d.Comment != 0 && (d.Comment == quote || strings.ContainsRune(separator, d.Comment)):
+		return fmt.Errorf("%w: comment %q", errInvalidDialect, d.Comment)
+	case d.LineTerminator != "" && d.LineTerminator != "\n" && d.LineTerminator != "\r\n" && d.LineTerminator != "\r":
+		return fmt.Errorf("%w: line terminator %q", errInvalidDialect, d.LineTerminator)
+	}
+	return nil
+}
+
+// usesEncodingCSV tells whether encoding/csv reads and writes the dialect.
+func (d Dialect) usesEncodingCSV() bool {
+	return utf8.RuneCountInString(d.separator()) == 1 && d.quote() == '"' &&
+		(d.Escape == 0 || d.Escape == '"') &&
+		(d.LineTerminator == "" || d.LineTerminator == "\n" || d.LineTerminator == "\r\n")
+}
+
+// NewDialectCSVReader creates a CSV reader parsing in with the given dialect:
+// a csv.Reader when encoding/csv supports it, or else a DialectReader. An
+// invalid dialect is reported by the first read.
+func NewDialectCSVReader(in io.Reader, d Dialect) CSVReader {
+	if d.validate() != nil || !d.usesEncodingCSV() {
+		return NewDialectReader(in, d)
+	}
+	reader := csv.NewReader(in)
+	reader.Comma, _ = utf8.DecodeRuneInString(d.separator())
+	reader.Comment = d.Comment
+	reader.TrimLeadingSpace = d.TrimLeadingSpace
+	reader.LazyQuotes = d.LazyQuotes
+	return reader
+}
+
+// NewDialectCSVWriter creates a SafeCSVWriter formatting CSV with the given
+// dialect, with a csv.Writer when encoding/csv supports it, or else with a
+// DialectWriter, the embedded csv.Writer being then unused. An invalid
+// dialect is reported by the first write.
+func NewDialectCSVWriter(out io.Writer, d Dialect) *SafeCSVWriter {
+	writer := NewSafeCSVWriter(csv.NewWriter(out))
+	if d.validate() != nil || !d.usesEncodingCSV() {
+		writer.writer = NewDialectWriter(out, d)
+		return writer
+	}
+	writer.out, writer.dialect = out, d
+	writer.Comma, _ = utf8.DecodeRuneInString(d.separator())
+	writer.UseCRLF = d.LineTerminator == "\r\n"
+	return writer
+}
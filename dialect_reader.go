@@ -0,0 +1,278 @@
+package gocsv
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DialectReader is a CSVReader parsing the dialects that encoding/csv does
+// not support: any quote character, a backslash-like escape character,
+// separators of several characters like "||" or "~|~", and "\r" line
+// terminators.
+type DialectReader struct {
+	// FieldsPerRecord is the number of fields expected in each record, as in
+	// csv.Reader: it is set by the first record when zero, and the records are
+	// not checked when negative. A record with another number of fields is
+	// returned along with a *csv.ParseError wrapping csv.ErrFieldCount.
+	FieldsPerRecord int
+
+	dialect     Dialect
+	separator   string
+	quote       rune
+	escape      rune // Equal to quote when quotes are doubled
+	in          *bufio.Reader
+	pending     rune // Rune read ahead, to be read again
+	hasPending  bool
+	line        int
+	column      int
+	atLineStart bool
+	err         error // Set when the dialect is invalid
+}
+
+// NewDialectReader creates a DialectReader parsing in with the given dialect.
+// An invalid dialect is reported by the first read.
+func NewDialectReader(in io.Reader, d Dialect) *DialectReader {
+	r := &DialectReader{
+		dialect:     d,
+		separator:   d.separator(),
+		quote:       d.quote(),
+		escape:      d.Escape,
+		in:          bufio.NewReader(in),
+		atLineStart: true,
+		err:         d.validate(),
+	}
+	if r.escape == 0 {
+		r.escape = r.quote
+	}
+	return r
+}
+
+// Read reads the next record. Empty lines and comment lines are skipped, and
+// io.EOF is returned once the input is exhausted.
+func (r *DialectReader) Read() ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for {
+		record, startLine, err := r.readRecord()
+		if err != nil {
+			return nil, err
+		} else if record == nil {
+			continue
+		}
+		if r.FieldsPerRecord == 0 {
+			r.FieldsPerRecord = len(record)
+		} else if r.FieldsPerRecord > 0 && len(record) != r.FieldsPerRecord {
+			return record, &csv.ParseError{StartLine: startLine, Line: startLine, Column: 1, Err: csv.ErrFieldCount}
+		}
+		return record, nil
+	}
+}
+
+// ReadAll reads the remaining records.
+func (r *DialectReader) ReadAll() ([][]string, error) {
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// readRecord reads the next line, returning a nil record for an empty or
+// comment line, and the line the record starts at.
+func (r *DialectReader) readRecord() ([]string, int, error) {
+	c, err := r.readRune()
+	if err != nil {
+		return nil, 0, err
+	}
+	startLine := r.line
+	if r.dialect.Comment != 0 && c == r.dialect.Comment {
+		for err == nil && !r.isLineEnd(c) {
+			c, err = r.readRune()
+		}
+		if err != nil && err != io.EOF {
+			return nil, startLine, err
+		}
+		return nil, startLine, nil
+	}
+	if r.isLineEnd(c) {
+		return nil, startLine, nil
+	}
+	r.unreadRune(c)
+
+	var record []string
+	for {
+		field, last, err := r.readField(startLine)
+		if err != nil {
+			return nil, startLine, err
+		}
+		record = append(record, field)
+		if last {
+			return record, startLine, nil
+		}
+	}
+}
+
+// readField reads the next field, and tells whether it is the last of the record.
+func (r *DialectReader) readField(startLine int) (string, bool, error) {
+	var field strings.Builder
+	c, err := r.readRune()
+	for r.dialect.TrimLeadingSpace && err == nil && c != '\n' && c != '\r' && unicode.IsSpace(c) {
+		c, err = r.readRune()
+	}
+	if err == io.EOF {
+		return "", true, nil
+	} else if err != nil {
+		return "", true, err
+	}
+
+	if c != r.quote {
+		for {
+			switch {
+			case r.escape != r.quote && c == r.escape:
+				if c, err = r.readRune(); err == io.EOF {
+					return "", true, r.parseError(startLine, csv.ErrQuote) // nothing to escape
+				} else if err == nil {
+					field.WriteRune(c)
+				}
+			case r.isSeparator(c):
+				return field.String(), false, nil
+			case r.isLineEnd(c):
+				return field.String(), true, nil
+			case c == r.quote && !r.dialect.LazyQuotes:
+				return "", true, r.parseError(startLine, csv.ErrBareQuote)
+			default:
+				field.WriteRune(c)
+			}
+			if err == nil {
+				c, err = r.readRune()
+			}
+			if err == io.EOF {
+				return field.String(), true, nil
+			} else if err != nil {
+				return "", true, err
+			}
+		}
+	}
+
+	for {
+		c, err = r.readRune()
+		if err == io.EOF {
+			if r.dialect.LazyQuotes {
+				return field.String(), true, nil
+			}
+			return "", true, r.parseError(startLine, csv.ErrQuote)
+		} else if err != nil {
+			return "", true, err
+		}
+		switch {
+		case r.escape != r.quote && c == r.escape:
+			if c, err = r.readRune(); err == io.EOF {
+				return "", true, r.parseError(startLine, csv.ErrQuote) // nothing to escape
+			} else if err != nil {
+				return "", true, err
+			}
+			field.WriteRune(c)
+		case c == r.quote:
+			next, err := r.readRune()
+			if err == io.EOF {
+				return field.String(), true, nil
+			} else if err != nil {
+				return "", true, err
+			}
+			switch {
+			case r.escape == r.quote && next == r.quote:
+				field.WriteRune(r.quote)
+			case r.isSeparator(next):
+				return field.String(), false, nil
+			case r.isLineEnd(next):
+				return field.String(), true, nil
+			case r.dialect.LazyQuotes:
+				field.WriteRune(c)
+				r.unreadRune(next)
+			default:
+				return "", true, r.parseError(startLine, csv.ErrQuote)
+			}
+		default:
+			field.WriteRune(c)
+		}
+	}
+}
+
+// readRune reads the next rune, keeping track of the line and column.
+func (r *DialectReader) readRune() (rune, error) {
+	if r.hasPending {
+		r.hasPending = false
+		return r.pending, nil
+	}
+	c, _, err := r.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r.atLineStart {
+		r.line++
+		r.column = 0
+		r.atLineStart = false
+	}
+	r.column++
+	if c == '\n' {
+		r.atLineStart = true
+	}
+	return c, nil
+}
+
+// unreadRune makes c, the rune just read, the next rune read.
+func (r *DialectReader) unreadRune(c rune) {
+	r.pending, r.hasPending = c, true
+}
+
+// isSeparator tells whether c, the rune just read, starts a separator, which
+// is then read.
+func (r *DialectReader) isSeparator(c rune) bool {
+	first, size := utf8.DecodeRuneInString(r.separator)
+	if c != first {
+		return false
+	}
+	rest := r.separator[size:]
+	if rest == "" {
+		return true
+	}
+	if next, err := r.in.Peek(len(rest)); err != nil || string(next) != rest {
+		return false
+	}
+	r.in.Discard(len(rest))
+	r.column += utf8.RuneCountInString(rest)
+	return true
+}
+
+// isLineEnd tells whether c, the rune just read, ends a line: "\n", "\r\n",
+// which is then read, or "\r" alone when it is the line terminator.
+func (r *DialectReader) isLineEnd(c rune) bool {
+	if c == '\n' {
+		return true
+	} else if c != '\r' {
+		return false
+	}
+	if next, err := r.in.Peek(1); err == nil && next[0] == '\n' {
+		r.readRune()
+		return true
+	}
+	if r.dialect.LineTerminator == "\r" {
+		r.atLineStart = true
+		return true
+	}
+	return false
+}
+
+func (r *DialectReader) parseError(startLine int, err error) error {
+	return &csv.ParseError{StartLine: startLine, Line: r.line, Column: r.column, Err: err}
+}
@@ -0,0 +1,88 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDialectReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		in      string
+		records [][]string
+	}{
+		{"backslash escapes", Dialect{Delimiter: '\t', Escape: '\\'}, "a\\\tb\tc\\\\d\n\"e\\\"f\"\tg\\\nh\n", [][]string{{"a\tb", `c\d`}, {`e"f`, "g\nh"}}},
+		{"single quotes", Dialect{Quote: '\''}, "'it''s',\"a\"\n'b\nc',d\n", [][]string{{"it's", `"a"`}, {"b\nc", "d"}}},
+		{"separator", Dialect{Separator: "||"}, "a|b||c||\r\n\"d||e\"||f||g\r\n", [][]string{{"a|b", "c", ""}, {"d||e", "f", "g"}}},
+		{"unicode separator", Dialect{Separator: "~¦~"}, "a~¦b~¦~c\n", [][]string{{"a~¦b", "c"}}},
+		{"CR terminator", Dialect{Quote: '\'', LineTerminator: "\r"}, "a,b\r'c\rd',e\r", [][]string{{"a", "b"}, {"c\rd", "e"}}},
+		{"comments and empty lines", Dialect{Quote: '\'', Comment: '#'}, "# header\n\na,b\n\r\n#\nc,'#d'\n", [][]string{{"a", "b"}, {"c", "#d"}}},
+		{"trim", Dialect{Quote: '\'', TrimLeadingSpace: true}, "a,  b\n  'c ', d", [][]string{{"a", "b"}, {"c ", "d"}}},
+		{"lazy quotes", Dialect{Quote: '\'', LazyQuotes: true}, "a'b,'c'd'\n'e", [][]string{{"a'b", "c'd"}, {"e"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewDialectReader(strings.NewReader(test.in), test.dialect)
+			r.FieldsPerRecord = -1
+			records, err := r.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(records, test.records) {
+				t.Fatalf("expected %q, got %q", test.records, records)
+			}
+		})
+	}
+}
+
+func TestDialectReaderErrors(t *testing.T) {
+	single, escaped := Dialect{Quote: '\''}, Dialect{Escape: '\\', LazyQuotes: true}
+	tests := []struct {
+		name    string
+		dialect Dialect
+		in      string
+		line    int
+		err     error
+	}{
+		{"bare quote", single, "a,b\nc,d'e\n", 2, csv.ErrBareQuote},
+		{"extraneous quote", single, "a,b\n'c\nd'e,f\n", 3, csv.ErrQuote},
+		{"missing quote", single, "a,b\nc,'d\n", 2, csv.ErrQuote},
+		{"trailing escape", escaped, "a,b\nc,d\\", 2, csv.ErrQuote},
+		{"trailing escape in quotes", escaped, "a,b\nc,\"d\\", 2, csv.ErrQuote},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewDialectReader(strings.NewReader(test.in), test.dialect)
+			_, err := r.ReadAll()
+			var parseError *csv.ParseError
+			if !errors.As(err, &parseError) || !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			if parseError.Line != test.line {
+				t.Fatalf("expected the error on line %d, got %d", test.line, parseError.Line)
+			}
+		})
+	}
+}
+
+func TestDialectReaderFieldCount(t *testing.T) {
+	r := NewDialectReader(strings.NewReader("a,b\nc\nd,e\n"), Dialect{Quote: '\''})
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	record, err := r.Read()
+	if !errors.Is(err, csv.ErrFieldCount) || !reflect.DeepEqual(record, []string{"c"}) {
+		t.Fatalf("expected the record along with ErrFieldCount, got %q, %v", record, err)
+	}
+	if record, err = r.Read(); err != nil || !reflect.DeepEqual(record, []string{"d", "e"}) {
+		t.Fatalf("unexpected record %q, %v", record, err)
+	}
+	if _, err = r.Read(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package gocsv
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type dialectSample struct {
+	Name  string  `csv:"name;omitempty"`
+	Price float64 `csv:"price"`
+}
+
+func TestDialectDecouplesTagSeparator(t *testing.T) {
+	samples := []dialectSample{{"Smith, John", 1.5}, {"Doe", 2}}
+
+	var buf bytes.Buffer
+	if err := Marshal(samples, &buf, WithTagSeparator(";"), WithDialect(DialectRFC4180)); err != nil {
+		t.Fatal(err)
+	}
+	expected := "name,price\r\n\"Smith, John\",1.5\r\nDoe,2\r\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+
+	var decoded []dialectSample
+	if err := Unmarshal(&buf, &decoded, WithTagSeparator(";"), WithDialect(DialectRFC4180)); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[0] != samples[0] || decoded[1] != samples[1] {
+		t.Fatalf("unexpected samples %+v", decoded)
+	}
+}
+
+func TestDialectPresets(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		out     string
+	}{
+		{"RFC4180", DialectRFC4180, "name,price\r\na;b,1\r\n"},
+		{"Excel", DialectExcel, "name,price\r\na;b,1\r\n"},
+		{"ExcelSemicolon", DialectExcelSemicolon, "name;price\r\n\"a;b\";1\r\n"},
+		{"TSV", DialectTSV, "name\tprice\na;b\t1\n"},
+		{"Unix", DialectUnix, "name,price\na;b,1\n"},
+		{"zero", Dialect{}, "name,price\na;b,1\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer := NewDialectCSVWriter(&buf, test.dialect)
+			if err := MarshalCSV([]dialectSample{{"a;b", 1}}, writer, WithTagSeparator(";")); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != test.out {
+				t.Fatalf("expected %q, got %q", test.out, buf.String())
+			}
+
+			var decoded []dialectSample
+			reader := NewDialectCSVReader(strings.NewReader(test.out), test.dialect)
+			if err := UnmarshalCSV(reader, &decoded, WithTagSeparator(";")); err != nil {
+				t.Fatal(err)
+			}
+			if len(decoded) != 1 || decoded[0].Name != "a;b" || decoded[0].Price != 1 {
+				t.Fatalf("unexpected samples %+v", decoded)
+			}
+		})
+	}
+}
+
+func TestDialectReaderSettings(t *testing.T) {
+	in := "# exported today\nname;price\n  Bob;  1.5\n# total\n  Alice;2\n"
+	dialect := Dialect{Delimiter: ';', Comment: '#', TrimLeadingSpace: true}
+
+	var samples []dialectSample
+	if err := UnmarshalString(in, &samples, WithTagSeparator(";"), WithDialect(dialect)); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Name != "Bob" || samples[0].Price != 1.5 || samples[1].Name != "Alice" {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+}
+
+func TestDialectNative(t *testing.T) {
+	samples := []dialectSample{{"Smith, 'John'", 1.5}, {`C:\data`, 2}}
+	tests := []struct {
+		dialect Dialect
+		out     string
+	}{
+		{Dialect{Quote: '\''}, "name,price\n'Smith, ''John''',1.5\nC:\\data,2\n"},
+		{Dialect{Escape: '\\'}, "name,price\n\"Smith, 'John'\",1.5\n\"C:\\\\data\",2\n"},
+		{Dialect{Separator: "~|~", LineTerminator: "\r"}, "name~|~price\rSmith, 'John'~|~1.5\rC:\\data~|~2\r"},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := Marshal(samples, &buf, WithTagSeparator(";"), WithDialect(test.dialect)); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.out {
+			t.Fatalf("%+v: expected %q, got %q", test.dialect, test.out, buf.String())
+		}
+
+		var decoded []dialectSample
+		if err := Unmarshal(&buf, &decoded, WithTagSeparator(";"), WithDialect(test.dialect)); err != nil {
+			t.Fatal(err)
+		}
+		if len(decoded) != 2 || decoded[0] != samples[0] || decoded[1] != samples[1] {
+			t.Fatalf("%+v: unexpected samples %+v", test.dialect, decoded)
+		}
+	}
+}
+
+func TestDialectInvalid(t *testing.T) {
+	for _, dialect := range []Dialect{
+		{Separator: "'|", Quote: '\''},
+		{Separator: "\r\n"},
+		{Quote: '\n'},
+		{Separator: "|", Comment: '|'},
+		{Separator: `\`, Escape: '\\'},
+		{LineTerminator: ";"},
+	} {
+		var samples []dialectSample
+		err := UnmarshalString("name,price\nBob,1\n", &samples, WithDialect(dialect))
+		if !errors.Is(err, errInvalidDialect) {
+			t.Fatalf("%+v: expected an invalid dialect error, got %v", dialect, err)
+		}
+		err = Marshal([]dialectSample{{"Bob", 1}}, &bytes.Buffer{}, WithDialect(dialect))
+		if !errors.Is(err, errInvalidDialect) {
+			t.Fatalf("%+v: expected an invalid dialect error, got %v", dialect, err)
+		}
+	}
+}
+
+func TestDialectAutoDialect(t *testing.T) {
+	in := "name\tprice\n  Bob\t1.5\n"
+
+	var samples []dialectSample
+	if err := UnmarshalString(in, &samples, WithTagSeparator(";"), WithDialect(Dialect{TrimLeadingSpace: true}), WithAutoDialect()); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Name != "Bob" || samples[0].Price != 1.5 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+}
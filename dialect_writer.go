@@ -0,0 +1,152 @@
+package gocsv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DialectWriter is a CSVWriter formatting the dialects that encoding/csv does
+// not support: any quote character, a backslash-like escape character,
+// separators of several characters like "||" or "~|~", and "\r" line
+// terminators.
+type DialectWriter struct {
+	dialect        Dialect
+	separator      string
+	quote          rune
+	escape         rune // Equal to quote when quotes are doubled
+	lineTerminator string
+	out            *bufio.Writer
+	err            error // Set when the dialect is invalid
+}
+
+// NewDialectWriter creates a DialectWriter writing to out with the given
+// dialect. An invalid dialect is reported by the first write.
+func NewDialectWriter(out io.Writer, d Dialect) *DialectWriter {
+	w := &DialectWriter{
+		dialect:        d,
+		separator:      d.separator(),
+		quote:          d.quote(),
+		escape:         d.Escape,
+		lineTerminator: d.LineTerminator,
+		out:            bufio.NewWriter(out),
+		err:            d.validate(),
+	}
+	if w.escape == 0 {
+		w.escape = w.quote
+	}
+	if w.lineTerminator == "" {
+		w.lineTerminator = "\n"
+	}
+	return w
+}
+
+// Write writes a record. The fields holding a separator, a quote, an escape
+// character, a line break or leading space are quoted. The output is
+// buffered: Flush must be called once done.
+func (w *DialectWriter) Write(record []string) error {
+	return w.writeQuoted(record, nil)
+}
+
+func (w *DialectWriter) writeQuoted(record []string, quoting []QuotePolicy) error {
+	if w.err != nil {
+		return w.err
+	}
+	for i, field := range record {
+		if i > 0 {
+			if _, err := w.out.WriteString(w.separator); err != nil {
+				return err
+			}
+		}
+		policy := QuoteMinimal
+		if quoting != nil {
+			policy = quoting[i]
+		}
+		var err error
+		switch {
+		case policy == QuoteNone:
+			err = w.writeEscaped(field, i == 0)
+		case policy == QuoteAll || w.fieldNeedsQuotes(field, i == 0):
+			err = w.writeQuotedField(field)
+		default:
+			_, err = w.out.WriteString(field)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.out.WriteString(w.lineTerminator)
+	return err
+}
+
+// writeQuotedField writes field between quotes, the quotes and escape
+// characters being preceded by the escape character.
+func (w *DialectWriter) writeQuotedField(field string) error {
+	if _, err := w.out.WriteRune(w.quote); err != nil {
+		return err
+	}
+	for _, c := range field {
+		if c == w.quote || c == w.escape {
+			if _, err := w.out.WriteRune(w.escape); err != nil {
+				return err
+			}
+		}
+		if _, err := w.out.WriteRune(c); err != nil {
+			return err
+		}
+	}
+	_, err := w.out.WriteRune(w.quote)
+	return err
+}
+
+// writeEscaped writes field unquoted, the characters that would need quotes
+// being preceded by the escape character. For a separator, the escape
+// character precedes its first character.
+func (w *DialectWriter) writeEscaped(field string, first bool) error {
+	for i, c := range field {
+		if strings.HasPrefix(field[i:], w.separator) || c == w.quote || c == w.escape || c == '\r' || c == '\n' ||
+			(first && i == 0 && w.dialect.Comment != 0 && c == w.dialect.Comment) {
+			if w.escape == w.quote {
+				return fmt.Errorf("%w: %q", errNoEscape, field)
+			}
+			if _, err := w.out.WriteRune(w.escape); err != nil {
+				return err
+			}
+		}
+		if _, err := w.out.WriteRune(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes the buffered records to the underlying writer.
+func (w *DialectWriter) Flush() {
+	w.out.Flush()
+}
+
+// Error returns the error of a previous Write or Flush.
+func (w *DialectWriter) Error() error {
+	if w.err != nil {
+		return w.err
+	}
+	_, err := w.out.Write(nil)
+	return err
+}
+
+// fieldNeedsQuotes tells whether field must be quoted to be read back as is.
+// A first field starting with the comment character is quoted too.
+func (w *DialectWriter) fieldNeedsQuotes(field string, first bool) bool {
+	if field == "" {
+		return false
+	}
+	if strings.Contains(field, w.separator) || strings.ContainsRune(field, w.quote) ||
+		strings.ContainsRune(field, w.escape) || strings.ContainsAny(field, "\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r) || (first && w.dialect.Comment != 0 && r == w.dialect.Comment)
+}
@@ -0,0 +1,97 @@
+package gocsv
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDialectWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		records [][]string
+		out     string
+	}{
+		{"backslash escapes", Dialect{Delimiter: '\t', Escape: '\\'}, [][]string{{"a\tb", `c\d`, "plain"}, {`e"f`, "g\nh", ""}}, "\"a\tb\"\t\"c\\\\d\"\tplain\n\"e\\\"f\"\t\"g\nh\"\t\n"},
+		{"single quotes", Dialect{Quote: '\'', LineTerminator: "\r\n"}, [][]string{{"it's", `"a"`, " b"}}, "'it''s',\"a\",' b'\r\n"},
+		{"separator", Dialect{Separator: "~|~"}, [][]string{{"a|b", "c~|~d", "e~"}}, "a|b~|~\"c~|~d\"~|~e~\n"},
+		{"comment", Dialect{Quote: '\'', Comment: '#'}, [][]string{{"#a", "#b"}}, "'#a',#b\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewDialectWriter(&buf, test.dialect)
+			for _, record := range test.records {
+				if err := w.Write(record); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != test.out {
+				t.Fatalf("expected %q, got %q", test.out, buf.String())
+			}
+
+			records, err := NewDialectReader(&buf, test.dialect).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range records {
+				for j := range records[i] {
+					if records[i][j] != test.records[i][j] {
+						t.Fatalf("expected %q to be read back, got %q", test.records[i][j], records[i][j])
+					}
+				}
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestDialectWriterError(t *testing.T) {
+	w := NewDialectWriter(failingWriter{}, Dialect{Quote: '\''})
+	w.Write([]string{"a", "b"})
+	w.Flush()
+	if err := w.Error(); err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected the write error, got %v", err)
+	}
+
+	// the fields overflowing the buffer report the error
+	for _, quoting := range [][]QuotePolicy{{QuoteMinimal, QuoteMinimal}, {QuoteAll, QuoteAll}, {QuoteNone, QuoteNone}} {
+		w = NewDialectWriter(failingWriter{}, Dialect{Escape: '\\'})
+		if err := w.writeQuoted([]string{strings.Repeat("a", 5000), "b"}, quoting); err == nil || err.Error() != "disk full" {
+			t.Fatalf("%v: expected the write error, got %v", quoting, err)
+		}
+	}
+}
+
+func TestDialectWriterEscapeSeparator(t *testing.T) {
+	dialect := Dialect{Separator: "~|~", Escape: '\\'}
+	record := []string{"a~b|c", "~|~|~", "d"}
+	var buf bytes.Buffer
+	w := NewDialectWriter(&buf, dialect)
+	if err := w.writeQuoted(record, []QuotePolicy{QuoteNone, QuoteNone, QuoteNone}); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+	// only the separators are escaped, not each of their characters
+	if expected := "a~b|c~|~\\~|\\~|~~|~d\n"; buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+	records, err := NewDialectReader(&buf, dialect).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || strings.Join(records[0], "/") != strings.Join(record, "/") {
+		t.Fatalf("expected %q to be read back, got %q", record, records)
+	}
+}
@@ -0,0 +1,164 @@
+package gocsv
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// --------------------------------------------------------------------------
+// Dynamic indexed columns
+//
+// A slice field tagged with `csv[]:"*"` is mapped to indexed columns, like
+// with a fixed `csv[]:"N"` length, but the indexes are found in the header row
+// when decoding, e.g. "items[7].sku", and in the values when encoding, where
+// the longest slice gives the number of columns.
+//
+// Similarly, a map field with string keys tagged with the prefix option, e.g.
+// `csv:"attr,prefix"`, holds the columns whose header starts with its key
+// followed by FieldsCombiner, e.g. "attr.color", keyed by the rest of the
+// header. When encoding, the sorted union of the keys of the maps gives the
+// columns.
+
+// dynamicArrayLength is the csv[] tag value of a dynamic slice.
+const dynamicArrayLength = "*"
+
+// maxDynamicIndex bounds the indexes read from a header row, so that a
+// header such as "items[999999999].sku" cannot make decoding allocate a huge slice.
+const maxDynamicIndex = 1 << 16
+
+// dynamicFieldInfo is a dynamic slice field, whose columns are inserted at
+// position pos of the fields of its struct info.
+type dynamicFieldInfo struct {
+	fieldInfo
+	pos int
+}
+
+// elemFieldInfos returns the configured fieldInfos of the element idx of the
+// dynamic slice: one per field of the element when it is a struct.
+func (d *dynamicFieldInfo) elemFieldInfos(cfg *Config, idx int) []fieldInfo {
+	if d.children == nil {
+		return []fieldInfo{configureFieldInfo(cfg, getArrayElemFieldInfo(&d.fieldInfo, idx, nil))}
+	}
+	fieldInfos := make([]fieldInfo, len(d.children))
+	for i := range d.children {
+		fieldInfos[i] = configureFieldInfo(cfg, getArrayElemFieldInfo(&d.fieldInfo, idx, &d.children[i]))
+	}
+	return fieldInfos
+}
+
+// mapEntryFieldInfo returns the configured fieldInfo of the entry mapKey of
+// the prefix map field.
+func (d *dynamicFieldInfo) mapEntryFieldInfo(cfg *Config, mapKey string) fieldInfo {
+	entryFieldInfo := d.fieldInfo
+	entryFieldInfo.dynamic = false
+	entryFieldInfo.mapKey = mapKey
+	entryFieldInfo.keys = make([]string, len(d.keys))
+	for i, key := range d.keys {
+		entryFieldInfo.keys[i] = key + cfg.FieldsCombiner + mapKey
+	}
+	return configureFieldInfo(cfg, entryFieldInfo)
+}
+
+// expandDynamicFields returns info where each dynamic slice or map is replaced by the
+// fields returned by expand.
+func expandDynamicFields(info *structInfo, expand func(d *dynamicFieldInfo) []fieldInfo) *structInfo {
+	if len(info.dynamic) == 0 {
+		return info
+	}
+	fieldsList := make([]fieldInfo, 0, len(info.Fields))
+	next := 0
+	for i := range info.dynamic {
+		d := &info.dynamic[i]
+		fieldsList = append(fieldsList, info.Fields[next:d.pos]...)
+		fieldsList = append(fieldsList, expand(d)...)
+		next = d.pos
+	}
+	fieldsList = append(fieldsList, info.Fields[next:]...)
+	return &structInfo{Fields: fieldsList, remain: info.remain}
+}
+
+// expandDynamicFieldsForHeaders returns info completed with the columns of its
+// dynamic slices and maps that are found in headers.
+func expandDynamicFieldsForHeaders(cfg *Config, info *structInfo, headers []string) *structInfo {
+	return expandDynamicFields(info, func(d *dynamicFieldInfo) []fieldInfo {
+		var fieldsList []fieldInfo
+		found := make(map[string]bool)
+		if d.prefix {
+			for _, header := range headers {
+				for _, key := range d.keys {
+					prefix := cfg.normalize(key + cfg.FieldsCombiner)
+					mapKey := strings.TrimPrefix(header, prefix)
+					if mapKey != header && mapKey != "" && !found[mapKey] {
+						found[mapKey] = true
+						fieldsList = append(fieldsList, d.mapEntryFieldInfo(cfg, mapKey))
+					}
+				}
+			}
+			return fieldsList
+		}
+		for _, header := range headers {
+			for _, idx := range getHeaderIndexes(header) {
+				for _, elemFieldInfo := range d.elemFieldInfos(cfg, idx) {
+					key := elemFieldInfo.getFirstKey()
+					if !found[key] && elemFieldInfo.matchesKey(header) {
+						found[key] = true
+						fieldsList = append(fieldsList, elemFieldInfo)
+					}
+				}
+			}
+		}
+		return fieldsList
+	})
+}
+
+// expandDynamicFieldsForValues returns info completed with the columns of its
+// dynamic slices and maps needed by the count values returned by value.
+func expandDynamicFieldsForValues(cfg *Config, info *structInfo, count int, value func(i int) reflect.Value) *structInfo {
+	return expandDynamicFields(info, func(d *dynamicFieldInfo) []fieldInfo {
+		if d.prefix {
+			keys := make(map[string]struct{})
+			for i := 0; i < count; i++ {
+				addMapKeys(keys, getInnerFieldValue(value(i), d.IndexChain))
+			}
+			fieldsList := make([]fieldInfo, 0, len(keys))
+			for _, mapKey := range sortedKeys(keys) {
+				fieldsList = append(fieldsList, d.mapEntryFieldInfo(cfg, mapKey))
+			}
+			return fieldsList
+		}
+		length := 0
+		for i := 0; i < count; i++ {
+			field := getInnerFieldValue(value(i), d.IndexChain)
+			if field.IsValid() && field.Kind() == reflect.Slice && field.Len() > length {
+				length = field.Len()
+			}
+		}
+		var fieldsList []fieldInfo
+		for idx := 0; idx < length; idx++ {
+			fieldsList = append(fieldsList, d.elemFieldInfos(cfg, idx)...)
+		}
+		return fieldsList
+	})
+}
+
+// getHeaderIndexes returns the indexes written between brackets in header,
+// e.g. 7 for "items[7].sku".
+func getHeaderIndexes(header string) []int {
+	var indexes []int
+	for {
+		start := strings.IndexByte(header, '[')
+		if start < 0 {
+			return indexes
+		}
+		header = header[start+1:]
+		end := strings.IndexByte(header, ']')
+		if end < 0 {
+			return indexes
+		}
+		if idx, err := strconv.Atoi(header[:end]); err == nil && idx >= 0 && idx <= maxDynamicIndex {
+			indexes = append(indexes, idx)
+		}
+		header = header[end+1:]
+	}
+}
@@ -0,0 +1,218 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type dynamicItem struct {
+	SKU string `csv:"sku"`
+	Qty int    `csv:"qty"`
+}
+
+type dynamicOrder struct {
+	ID    string        `csv:"id"`
+	Items []dynamicItem `csv:"items" csv[]:"*"`
+	Tags  []string      `csv:"tags" csv[]:"*"`
+	Total float64       `csv:"total"`
+}
+
+func TestDynamicColumnsDecode(t *testing.T) {
+	in := `id,items[0].sku,items[0].qty,items[2].sku,items[2].qty,tags[1],total
+o1,a,1,c,3,x,9.5
+o2,b,2,,,,1`
+	var orders []dynamicOrder
+	if err := UnmarshalString(in, &orders); err != nil {
+		t.Fatal(err)
+	}
+	expected := []dynamicOrder{
+		{ID: "o1", Items: []dynamicItem{{"a", 1}, {}, {"c", 3}}, Tags: []string{"", "x"}, Total: 9.5},
+		{ID: "o2", Items: []dynamicItem{{"b", 2}, {}, {}}, Tags: []string{"", ""}, Total: 1},
+	}
+	if !reflect.DeepEqual(orders, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, orders)
+	}
+
+	um, err := NewUnmarshaller(csv.NewReader(strings.NewReader(in)), dynamicOrder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := um.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected[0]) {
+		t.Fatalf("expected %+v, got %+v", expected[0], v)
+	}
+}
+
+func TestDynamicColumnsDecodeNormalized(t *testing.T) {
+	var orders []dynamicOrder
+	if err := UnmarshalString("ID,ITEMS[1].SKU\no1,b", &orders, WithHeaderNormalizer(strings.ToLower)); err != nil {
+		t.Fatal(err)
+	}
+	if len(orders[0].Items) != 2 || orders[0].Items[1].SKU != "b" {
+		t.Fatalf("unexpected order %+v", orders[0])
+	}
+}
+
+func TestDynamicColumnsDecodeSparse(t *testing.T) {
+	in := `id,items[7].sku,items[3].qty,items[0].sku,tags[5]
+o1,h,4,a,z`
+	var orders []dynamicOrder
+	if err := UnmarshalString(in, &orders); err != nil {
+		t.Fatal(err)
+	}
+	items := orders[0].Items
+	if len(items) != 8 || items[7].SKU != "h" || items[3].Qty != 4 || items[0].SKU != "a" {
+		t.Fatalf("unexpected items %+v", items)
+	}
+	if len(orders[0].Tags) != 6 || orders[0].Tags[5] != "z" {
+		t.Fatalf("unexpected tags %q", orders[0].Tags)
+	}
+}
+
+func TestDynamicColumnsIndexLimit(t *testing.T) {
+	var orders []dynamicOrder
+	if err := UnmarshalString("id,items[999999999].sku\no1,b", &orders); err != nil {
+		t.Fatal(err)
+	}
+	if len(orders[0].Items) != 0 {
+		t.Fatalf("expected a huge index to be ignored, got %d items", len(orders[0].Items))
+	}
+}
+
+func TestDynamicColumnsEncode(t *testing.T) {
+	orders := []dynamicOrder{
+		{ID: "o1", Items: []dynamicItem{{"a", 1}}, Total: 1},
+		{ID: "o2", Items: []dynamicItem{{"b", 2}, {"c", 3}}, Tags: []string{"x"}, Total: 2},
+	}
+	out, err := MarshalString(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `id,items[0].sku,items[0].qty,items[1].sku,items[1].qty,tags[0],total
+o1,a,1,,,,1
+o2,b,2,c,3,x,2
+`
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+
+	var decoded []dynamicOrder
+	if err := UnmarshalString(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || !reflect.DeepEqual(decoded[1], orders[1]) {
+		t.Fatalf("expected the second order to round trip, got %+v", decoded)
+	}
+}
+
+type prefixProduct struct {
+	SKU    string             `csv:"sku"`
+	Attrs  map[string]string  `csv:"attr,prefix"`
+	Prices map[string]float64 `csv:"price,prefix"`
+	Sizes  map[string][]int   `csv:"sizes,prefix,split=|"`
+}
+
+func TestPrefixMapDecode(t *testing.T) {
+	in := `sku,attr.color,price.eur,attr.material,price.usd,sizes.eu,other
+p1,red,1.5,wood,2,38|40,x
+p2,,3,,,,y`
+	var products []prefixProduct
+	if err := UnmarshalString(in, &products); err != nil {
+		t.Fatal(err)
+	}
+	expected := []prefixProduct{
+		{
+			SKU:    "p1",
+			Attrs:  map[string]string{"color": "red", "material": "wood"},
+			Prices: map[string]float64{"eur": 1.5, "usd": 2},
+			Sizes:  map[string][]int{"eu": {38, 40}},
+		},
+		{SKU: "p2", Prices: map[string]float64{"eur": 3}},
+	}
+	if !reflect.DeepEqual(products, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, products)
+	}
+
+	var bad []prefixProduct
+	if err := UnmarshalString("sku,price.eur\np1,abc", &bad); err == nil {
+		t.Fatal("expected a conversion error")
+	}
+}
+
+func TestPrefixMapEncode(t *testing.T) {
+	products := []prefixProduct{
+		{SKU: "p1", Attrs: map[string]string{"material": "wood", "color": "red"}},
+		{SKU: "p2", Attrs: map[string]string{"size": "L"}, Prices: map[string]float64{"eur": 3}},
+	}
+	out, err := MarshalString(products, WithFieldsCombiner("_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `sku,attr_color,attr_material,attr_size,price_eur
+p1,red,wood,,
+p2,,,L,3
+`
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+
+	var decoded []prefixProduct
+	if err := UnmarshalString(out, &decoded, WithFieldsCombiner("_")); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, products) {
+		t.Fatalf("expected %+v, got %+v", products, decoded)
+	}
+}
+
+func TestPrefixInvalidType(t *testing.T) {
+	type sample struct {
+		Attrs []string `csv:"attr,prefix"`
+	}
+	var samples []sample
+	err := UnmarshalString("attr\nx", &samples)
+	if err == nil || !strings.Contains(err.Error(), "not a map with string keys") {
+		t.Fatalf("expected an invalid type error, got %v", err)
+	}
+}
+
+func TestPrefixColumnName(t *testing.T) {
+	type sample struct {
+		Prefix string `csv:"prefix"`
+	}
+	var samples []sample
+	if err := UnmarshalString("prefix\nMr", &samples); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Prefix != "Mr" {
+		t.Fatalf("expected the prefix column to be mapped, got %+v", samples[0])
+	}
+	out, err := MarshalString(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "prefix\nMr\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
+
+func TestPrefixMapValidation(t *testing.T) {
+	type sample struct {
+		Scores map[string]int `csv:"score,prefix,max=10"`
+	}
+	var samples []sample
+	if err := UnmarshalString("score.a,score.b\n1,2", &samples); err != nil {
+		t.Fatal(err)
+	}
+	err := UnmarshalString("score.a,score.b\n1,20", &samples)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Column != 2 {
+		t.Fatalf("expected a validation error on the second column, got %v", err)
+	}
+}
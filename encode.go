@@ -19,7 +19,7 @@ func newEncoder(out io.Writer) *encoder {
 	return &encoder{out}
 }
 
-func writeFromChan(writer CSVWriter, c <-chan interface{}, omitHeaders bool) error {
+func writeFromChan(cfg *Config, writer CSVWriter, c <-chan interface{}, omitHeaders bool) error {
 	// Get the first value. It wil determine the header structure.
 	firstValue, ok := <-c
 	if !ok {
@@ -30,26 +30,24 @@ func writeFromChan(writer CSVWriter, c <-chan interface{}, omitHeaders bool) err
 		return err
 	}
 	inInnerWasPointer := inType.Kind() == reflect.Ptr
-	inInnerStructInfo := getStructInfo(inType) // Get the inner struct info to get CSV annotations
-	csvHeadersLabels := make([]string, len(inInnerStructInfo.Fields))
-	for i, fieldInfo := range inInnerStructInfo.Fields { // Used to write the header (first line) in CSV
-		csvHeadersLabels[i] = fieldInfo.getFirstKey()
-	}
+	inInnerStructInfo := getStructInfo(cfg, inType) // Get the inner struct info to get CSV annotations
+	// The columns of dynamic slices and remain fields are those of the first value
+	first := func(int) reflect.Value { return inValue }
+	inInnerStructInfo = expandDynamicFieldsForValues(cfg, inInnerStructInfo, 1, first)
+	remainKeys := getRemainKeys(inInnerStructInfo, 1, first)
+	csvHeadersLabels := getHeaderLabels(inInnerStructInfo, remainKeys) // Used to write the header (first line) in CSV
+	headerQuoting, recordQuoting := cfg.recordQuoting(inType, inInnerStructInfo, remainKeys)
 	if !omitHeaders {
-		if err := writer.Write(csvHeadersLabels); err != nil {
+		if err := writeRecord(writer, csvHeadersLabels, headerQuoting); err != nil {
 			return err
 		}
 	}
+	record := make([]string, len(csvHeadersLabels))
 	write := func(val reflect.Value) error {
-		for j, fieldInfo := range inInnerStructInfo.Fields {
-			csvHeadersLabels[j] = ""
-			inInnerFieldValue, err := getInnerField(val, inInnerWasPointer, fieldInfo.IndexChain) // Get the correct field header <-> position
-			if err != nil {
-				return err
-			}
-			csvHeadersLabels[j] = inInnerFieldValue
+		if err := fillRecord(record, val, inInnerWasPointer, inInnerStructInfo, remainKeys); err != nil {
+			return err
 		}
-		if err := writer.Write(csvHeadersLabels); err != nil {
+		if err := writeRecord(writer, record, recordQuoting); err != nil {
 			return err
 		}
 		return nil
@@ -70,7 +68,7 @@ func writeFromChan(writer CSVWriter, c <-chan interface{}, omitHeaders bool) err
 	return writer.Error()
 }
 
-func writeTo(writer CSVWriter, in interface{}, omitHeaders bool) error {
+func writeTo(cfg *Config, writer CSVWriter, in interface{}, omitHeaders bool) error {
 	inValue, inType := getConcreteReflectValueAndType(in) // Get the concrete type (not pointer) (Slice<?> or Array<?>)
 	if err := ensureInType(inType); err != nil {
 		return err
@@ -79,27 +77,23 @@ func writeTo(writer CSVWriter, in interface{}, omitHeaders bool) error {
 	if err := ensureInInnerType(inInnerType); err != nil {
 		return err
 	}
-	inInnerStructInfo := getStructInfo(inInnerType) // Get the inner struct info to get CSV annotations
-	csvHeadersLabels := make([]string, len(inInnerStructInfo.Fields))
-	for i, fieldInfo := range inInnerStructInfo.Fields { // Used to write the header (first line) in CSV
-		csvHeadersLabels[i] = fieldInfo.getFirstKey()
-	}
+	inInnerStructInfo := getStructInfo(cfg, inInnerType) // Get the inner struct info to get CSV annotations
+	inLen := inValue.Len()
+	inInnerStructInfo = expandDynamicFieldsForValues(cfg, inInnerStructInfo, inLen, inValue.Index)
+	remainKeys := getRemainKeys(inInnerStructInfo, inLen, inValue.Index)
+	csvHeadersLabels := getHeaderLabels(inInnerStructInfo, remainKeys) // Used to write the header (first line) in CSV
+	headerQuoting, recordQuoting := cfg.recordQuoting(inInnerType, inInnerStructInfo, remainKeys)
 	if !omitHeaders {
-		if err := writer.Write(csvHeadersLabels); err != nil {
+		if err := writeRecord(writer, csvHeadersLabels, headerQuoting); err != nil {
 			return err
 		}
 	}
-	inLen := inValue.Len()
+	record := make([]string, len(csvHeadersLabels))
 	for i := 0; i < inLen; i++ { // Iterate over container rows
-		for j, fieldInfo := range inInnerStructInfo.Fields {
-			csvHeadersLabels[j] = ""
-			inInnerFieldValue, err := getInnerField(inValue.Index(i), inInnerWasPointer, fieldInfo.IndexChain) // Get the correct field header <-> position
-			if err != nil {
-				return err
-			}
-			csvHeadersLabels[j] = inInnerFieldValue
+		if err := fillRecord(record, inValue.Index(i), inInnerWasPointer, inInnerStructInfo, remainKeys); err != nil {
+			return err
 		}
-		if err := writer.Write(csvHeadersLabels); err != nil {
+		if err := writeRecord(writer, record, recordQuoting); err != nil {
 			return err
 		}
 	}
@@ -107,6 +101,30 @@ func writeTo(writer CSVWriter, in interface{}, omitHeaders bool) error {
 	return writer.Error()
 }
 
+// getHeaderLabels returns the header (first line) written for the struct info,
+// followed by the keys of its remain field.
+func getHeaderLabels(inInnerStructInfo *structInfo, remainKeys []string) []string {
+	csvHeadersLabels := make([]string, len(inInnerStructInfo.Fields), len(inInnerStructInfo.Fields)+len(remainKeys))
+	for i, fieldInfo := range inInnerStructInfo.Fields {
+		csvHeadersLabels[i] = fieldInfo.getFirstKey()
+	}
+	return append(csvHeadersLabels, remainKeys...)
+}
+
+// fillRecord stores in record the CSV value of each field of inInner, followed
+// by the values of its remain field for remainKeys.
+func fillRecord(record []string, inInner reflect.Value, inInnerWasPointer bool, inInnerStructInfo *structInfo, remainKeys []string) error {
+	for j, fieldInfo := range inInnerStructInfo.Fields {
+		record[j] = ""
+		inInnerFieldValue, err := getInnerField(inInner, inInnerWasPointer, fieldInfo.IndexChain, &fieldInfo) // Get the correct field header <-> position
+		if err != nil {
+			return err
+		}
+		record[j] = inInnerFieldValue
+	}
+	return fillRemainRecord(record[len(inInnerStructInfo.Fields):], inInner, inInnerStructInfo, remainKeys)
+}
+
 func ensureStructOrPtr(t reflect.Type) error {
 	switch t.Kind() {
 	case reflect.Struct:
@@ -137,11 +155,11 @@ func ensureInInnerType(outInnerType reflect.Type) error {
 	return fmt.Errorf("cannot use " + outInnerType.String() + ", only struct supported")
 }
 
-func getInnerField(outInner reflect.Value, outInnerWasPointer bool, index []int) (string, error) {
+func getInnerField(outInner reflect.Value, outInnerWasPointer bool, index []int, fieldInfo *fieldInfo) (string, error) {
 	oi := outInner
 	if outInnerWasPointer {
 		if oi.IsNil() {
-			return "", nil
+			return fieldInfo.nullToken(), nil
 		}
 		oi = outInner.Elem()
 	}
@@ -155,15 +173,15 @@ func getInnerField(outInner reflect.Value, outInnerWasPointer bool, index []int)
 
 		item := oi.Index(i)
 		if len(index) > 1 {
-			return getInnerField(item, false, index[1:])
+			return getInnerField(item, false, index[1:], fieldInfo)
 		}
-		return getFieldAsString(item)
+		return getFieldAsString(item, fieldInfo)
 	}
 
 	// because pointers can be nil need to recurse one index at a time and perform nil check
 	if len(index) > 1 {
 		nextField := oi.Field(index[0])
-		return getInnerField(nextField, nextField.Kind() == reflect.Ptr, index[1:])
+		return getInnerField(nextField, nextField.Kind() == reflect.Ptr, index[1:], fieldInfo)
 	}
-	return getFieldAsString(oi.FieldByIndex(index))
+	return getFieldAsString(oi.FieldByIndex(index), fieldInfo)
 }
@@ -0,0 +1,159 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeError describes a CSV cell that could not be decoded into its struct field.
+type DecodeError struct {
+	Line   int    // Line of the record, starting at 1
+	Column int    // Column of the cell, starting at 1
+	Header string // Header of the column, after normalization, empty without header
+	Field  string // Path of the target struct field, e.g. "Address.City", empty if unknown
+	Value  string // Raw content of the cell
+	Err    error  // Underlying conversion error
+}
+
+func (e *DecodeError) Error() string {
+	field := e.Field
+	if field == "" {
+		field = "?"
+	}
+	return fmt.Sprintf("line %d, column %d (header %q, field %s): cannot decode %q: %v", e.Line, e.Column, e.Header, field, e.Value, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors is returned in collect mode (see WithCollectErrors) when some
+// cells could not be decoded. It lists every failing cell, in input order.
+type DecodeErrors struct {
+	Errors []*DecodeError
+
+	// Truncated is set when decoding stopped because there were more errors
+	// than Config.MaxErrors.
+	Truncated bool
+}
+
+func (e *DecodeErrors) Error() string {
+	if len(e.Errors) == 1 && !e.Truncated {
+		return e.Errors[0].Error()
+	}
+	lines := make([]string, 0, len(e.Errors)+1)
+	summary := fmt.Sprintf("%d decode errors", len(e.Errors))
+	if e.Truncated {
+		summary = "more than " + summary
+	}
+	lines = append(lines, summary+":")
+	for _, err := range e.Errors {
+		lines = append(lines, "\t"+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Is reports whether any of the collected errors matches target.
+func (e *DecodeErrors) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first collected error that matches target.
+func (e *DecodeErrors) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorCollector gathers decode errors up to a maximum, a max <= 0 meaning no limit.
+type errorCollector struct {
+	errs DecodeErrors
+	max  int
+}
+
+// add records err, and returns false once more than max errors were recorded.
+func (c *errorCollector) add(err *DecodeError) bool {
+	if c.max > 0 && len(c.errs.Errors) >= c.max {
+		c.errs.Truncated = true
+		return false
+	}
+	c.errs.Errors = append(c.errs.Errors, err)
+	return true
+}
+
+// newErrorCollector returns the collector of the errors in collect mode, or nil.
+func (cfg *Config) newErrorCollector() *errorCollector {
+	if !cfg.CollectErrors {
+		return nil
+	}
+	return &errorCollector{max: cfg.MaxErrors}
+}
+
+// collect records parseError, raised by the cell holding value in the column
+// of header, mapped to the field of rType described by fieldInfo, if any. It
+// returns the error that must stop the decoding: parseError itself without
+// collector, or the collected errors once there are too many of them.
+func (c *errorCollector) collect(parseError *csv.ParseError, header string, rType reflect.Type, fieldInfo *fieldInfo, value string) error {
+	if c == nil {
+		return parseError
+	}
+	decodeError := &DecodeError{
+		Line:   parseError.Line,
+		Column: parseError.Column,
+		Header: header,
+		Value:  value,
+		Err:    parseError.Err,
+	}
+	if fieldInfo != nil {
+		decodeError.Field = getFieldPath(rType, fieldInfo.IndexChain)
+	}
+	if !c.add(decodeError) {
+		return c.err()
+	}
+	return nil
+}
+
+// err returns the collected errors, or nil if there were none or no collector.
+func (c *errorCollector) err() error {
+	if c == nil || len(c.errs.Errors) == 0 {
+		return nil
+	}
+	return &c.errs
+}
+
+// getFieldPath returns the Go path of the field reached from rType by indexChain, e.g. "Items[0].SKU".
+func getFieldPath(rType reflect.Type, indexChain []int) string {
+	var path strings.Builder
+	for _, index := range indexChain {
+		for rType.Kind() == reflect.Ptr {
+			rType = rType.Elem()
+		}
+		switch rType.Kind() {
+		case reflect.Struct:
+			field := rType.Field(index)
+			if path.Len() > 0 {
+				path.WriteByte('.')
+			}
+			path.WriteString(field.Name)
+			rType = field.Type
+		case reflect.Slice, reflect.Array:
+			path.WriteString("[" + strconv.Itoa(index) + "]")
+			rType = rType.Elem()
+		default:
+			return path.String()
+		}
+	}
+	return path.String()
+}
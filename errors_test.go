@@ -0,0 +1,147 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type collectSample struct {
+	Name  string `csv:"name"`
+	Age   int    `csv:"age"`
+	Inner struct {
+		Score float64 `csv:"score"`
+	} `csv:"inner"`
+}
+
+func TestUnmarshalCollectErrors(t *testing.T) {
+	in := `name,age,inner.score
+a,1,1.5
+b,x,2
+c,3,y
+d,z,w`
+	var samples []collectSample
+	err := UnmarshalString(in, &samples, WithCollectErrors(0))
+
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) {
+		t.Fatalf("expected *DecodeErrors, got %v", err)
+	}
+	if len(samples) != 4 {
+		t.Fatalf("expected every row to be decoded, got %d", len(samples))
+	}
+	if samples[3].Name != "d" || samples[3].Age != 0 {
+		t.Fatalf("expected failing fields to be left unset, got %+v", samples[3])
+	}
+	if len(decodeErrs.Errors) != 4 || decodeErrs.Truncated {
+		t.Fatalf("expected 4 errors, got %v", decodeErrs)
+	}
+
+	first := decodeErrs.Errors[0]
+	if first.Line != 3 || first.Column != 2 || first.Header != "age" || first.Field != "Age" || first.Value != "x" {
+		t.Fatalf("unexpected first error %+v", first)
+	}
+	second := decodeErrs.Errors[1]
+	if second.Line != 4 || second.Column != 3 || second.Header != "inner.score" || second.Field != "Inner.Score" || second.Value != "y" {
+		t.Fatalf("unexpected second error %+v", second)
+	}
+
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatal("expected errors.Is to find strconv.ErrSyntax")
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || numErr.Num != "x" {
+		t.Fatalf("expected errors.As to find the first *strconv.NumError, got %v", numErr)
+	}
+	if !strings.HasPrefix(err.Error(), "4 decode errors:") {
+		t.Fatalf("unexpected error message %q", err.Error())
+	}
+}
+
+func TestUnmarshalCollectErrorsMax(t *testing.T) {
+	var samples []collectSample
+	err := UnmarshalString("name,age\na,x\nb,y\nc,z\nd,1", &samples, WithCollectErrors(2))
+
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) {
+		t.Fatalf("expected *DecodeErrors, got %v", err)
+	}
+	if len(decodeErrs.Errors) != 2 || !decodeErrs.Truncated {
+		t.Fatalf("expected 2 errors and truncation, got %v", decodeErrs)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected decoding to stop at the third error, got %d samples", len(samples))
+	}
+}
+
+func TestUnmarshalToChanCollectErrors(t *testing.T) {
+	c := make(chan collectSample)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- UnmarshalStringToChan("name,age\na,x\nb,2", c, WithCollectErrors(0))
+	}()
+	var samples []collectSample
+	for s := range c {
+		samples = append(samples, s)
+	}
+	err := <-errc
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs.Errors) != 1 {
+		t.Fatalf("expected a single collected error, got %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+}
+
+func TestUnmarshalWithoutHeadersCollectErrors(t *testing.T) {
+	var samples []collectSample
+	err := UnmarshalWithoutHeaders(strings.NewReader("a,x,1.5\nb,2,y\nc,3,4"), &samples, WithCollectErrors(0))
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs.Errors) != 2 {
+		t.Fatalf("expected 2 collected errors, got %v", err)
+	}
+	if len(samples) != 3 || samples[0].Name != "a" || samples[2].Inner.Score != 4 {
+		t.Fatalf("expected every row to be decoded, got %+v", samples)
+	}
+	second := decodeErrs.Errors[1]
+	if second.Line != 2 || second.Column != 3 || second.Field != "Inner.Score" || second.Value != "y" {
+		t.Fatalf("unexpected second error %+v", second)
+	}
+
+	samples = nil
+	err = UnmarshalWithoutHeaders(strings.NewReader("a,x,1\nb,y,2\nc,3,4"), &samples, WithCollectErrors(1))
+	if !errors.As(err, &decodeErrs) || !decodeErrs.Truncated || len(samples) != 1 {
+		t.Fatalf("expected decoding to stop at the second error, got %v and %d samples", err, len(samples))
+	}
+
+	c := make(chan collectSample, 3)
+	err = UnmarshalToChanWithoutHeaders(strings.NewReader("a,x,1\nb,2,3"), c, WithCollectErrors(0))
+	if !errors.As(err, &decodeErrs) || len(decodeErrs.Errors) != 1 || len(c) != 2 {
+		t.Fatalf("expected a single collected error and 2 samples, got %v and %d samples", err, len(c))
+	}
+}
+
+func TestUnmarshallerCollectErrors(t *testing.T) {
+	_, err := NewUnmarshaller(csv.NewReader(strings.NewReader("name,age\na,x")), collectSample{}, WithCollectErrors(0))
+	if err != errCollectUnsupported {
+		t.Fatalf("expected collect mode to be refused, got %v", err)
+	}
+}
+
+func TestTypedDecoderCollectErrors(t *testing.T) {
+	d, err := NewDecoder[collectSample](strings.NewReader("name,age\na,x\nb,2"), WithCollectErrors(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, err := d.ReadAll()
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs.Errors) != 1 {
+		t.Fatalf("expected a single collected error, got %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+}
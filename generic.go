@@ -0,0 +1,266 @@
+package gocsv
+
+import (
+	"context"
+	"io"
+	"reflect"
+)
+
+// --------------------------------------------------------------------------
+// Type-safe API
+//
+// The functions and types below mirror the interface{} based API, but take
+// the struct type as a type parameter so that passing a wrong container,
+// channel or callback shape is caught at compile time.
+
+// UnmarshalAs parses the CSV from the reader into a slice of T.
+// T must be a struct or a pointer to a struct.
+func UnmarshalAs[T any](in io.Reader, opts ...Option) ([]T, error) {
+	var out []T
+	if err := Unmarshal(in, &out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TypedDecoder reads CSV records one at a time and decodes them into values of type T.
+type TypedDecoder[T any] struct {
+	decoder   SimpleDecoder
+	converter *rowConverter
+	line      int
+}
+
+// NewDecoder creates a TypedDecoder reading from in. The header row is read
+// immediately and matched against the struct tags of T, which must be a struct
+// or a pointer to a struct.
+func NewDecoder[T any](in io.Reader, opts ...Option) (*TypedDecoder[T], error) {
+	cfg := newConfig(opts)
+	return newTypedDecoder[T](cfg, newSimpleDecoderFromReader(cfg, in))
+}
+
+// NewDecoderFromSimpleDecoder creates a TypedDecoder reading from the given SimpleDecoder.
+func NewDecoderFromSimpleDecoder[T any](in SimpleDecoder, opts ...Option) (*TypedDecoder[T], error) {
+	return newTypedDecoder[T](newConfig(opts), in)
+}
+
+func newTypedDecoder[T any](cfg *Config, in SimpleDecoder) (*TypedDecoder[T], error) {
+	elemType := reflect.TypeOf((*T)(nil)).Elem()
+	detectHeaderFor(in, elemType)
+	headers, err := in.GetCSVRow()
+	if err == io.EOF {
+		return nil, ErrEmptyCSVFile
+	} else if err != nil {
+		return nil, err
+	}
+	converter, err := newRowConverter(cfg, elemType, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedDecoder[T]{decoder: in, converter: converter, line: 1 + preambleLines(in)}, nil
+}
+
+// Read decodes the next CSV record. It returns io.EOF once the input is exhausted.
+// In collect mode (see WithCollectErrors), the errors gathered so far are
+// returned in place of io.EOF.
+func (d *TypedDecoder[T]) Read() (T, error) {
+	var v T
+	record, err := d.decoder.GetCSVRow()
+	if err == io.EOF {
+		if err := d.converter.collectedErrors(); err != nil {
+			return v, err
+		}
+		return v, io.EOF
+	} else if err != nil {
+		return v, err
+	}
+	d.line++
+	outInner, err := d.converter.convert(record, d.line)
+	if err != nil {
+		return v, err
+	}
+	return outInner.Interface().(T), nil
+}
+
+// Next is like Read, but returns ctx.Err() once ctx is done. The context is
+// checked before and after reading: a read blocked on the underlying reader is
+// not interrupted, and the record it returns is dropped if ctx is done by then.
+func (d *TypedDecoder[T]) Next(ctx context.Context) (T, error) {
+	var v T
+	if err := ctx.Err(); err != nil {
+		return v, err
+	}
+	v, err := d.Read()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		var zero T
+		return zero, ctxErr
+	}
+	return v, err
+}
+
+// ReadAll decodes all the remaining CSV records.
+func (d *TypedDecoder[T]) ReadAll() ([]T, error) {
+	var out []T
+	for {
+		v, err := d.Read()
+		if err == io.EOF {
+			return out, nil
+		} else if err != nil {
+			return out, err
+		}
+		out = append(out, v)
+	}
+}
+
+// TypedEncoder writes values of type T as CSV records. The header row is
+// written before the first value, or on Flush if no value was encoded.
+type TypedEncoder[T any] struct {
+	cfg               *Config
+	writer            CSVWriter
+	inInnerWasPointer bool
+	inInnerType       reflect.Type
+	inInnerStructInfo *structInfo
+	record            []string
+	recordQuoting     []QuotePolicy
+	remainKeys        []string
+	headerWritten     bool
+}
+
+// NewEncoder creates a TypedEncoder writing to out.
+// T must be a struct or a pointer to a struct.
+func NewEncoder[T any](out io.Writer, opts ...Option) (*TypedEncoder[T], error) {
+	cfg := newConfig(opts)
+	return newTypedEncoder[T](cfg, cfg.getCSVWriter(out))
+}
+
+// NewEncoderFromCSVWriter creates a TypedEncoder writing to the given CSVWriter.
+func NewEncoderFromCSVWriter[T any](out CSVWriter, opts ...Option) (*TypedEncoder[T], error) {
+	return newTypedEncoder[T](newConfig(opts), out)
+}
+
+func newTypedEncoder[T any](cfg *Config, out CSVWriter) (*TypedEncoder[T], error) {
+	inType := reflect.TypeOf((*T)(nil)).Elem()
+	inInnerWasPointer, inInnerType := false, inType
+	if inInnerType.Kind() == reflect.Ptr {
+		inInnerWasPointer, inInnerType = true, inInnerType.Elem()
+	}
+	if err := ensureInInnerType(inInnerType); err != nil {
+		return nil, err
+	}
+	inInnerStructInfo := getStructInfo(cfg, inInnerType) // Get the inner struct info to get CSV annotations
+	return &TypedEncoder[T]{
+		cfg:               cfg,
+		writer:            out,
+		inInnerWasPointer: inInnerWasPointer,
+		inInnerType:       inInnerType,
+		inInnerStructInfo: inInnerStructInfo,
+	}, nil
+}
+
+// writeHeader writes the header unless already done. The columns of dynamic
+// slices and remain fields are those of first, when valid.
+func (e *TypedEncoder[T]) writeHeader(first reflect.Value) error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	if first.IsValid() {
+		value := func(int) reflect.Value { return first }
+		e.inInnerStructInfo = expandDynamicFieldsForValues(e.cfg, e.inInnerStructInfo, 1, value)
+		e.remainKeys = getRemainKeys(e.inInnerStructInfo, 1, value)
+	}
+	header := getHeaderLabels(e.inInnerStructInfo, e.remainKeys)
+	e.record = make([]string, len(header))
+	var headerQuoting []QuotePolicy
+	headerQuoting, e.recordQuoting = e.cfg.recordQuoting(e.inInnerType, e.inInnerStructInfo, e.remainKeys)
+	return writeRecord(e.writer, header, headerQuoting)
+}
+
+// Encode writes v as a CSV record.
+func (e *TypedEncoder[T]) Encode(v T) error {
+	value := reflect.ValueOf(&v).Elem()
+	if err := e.writeHeader(value); err != nil {
+		return err
+	}
+	if err := fillRecord(e.record, value, e.inInnerWasPointer, e.inInnerStructInfo, e.remainKeys); err != nil {
+		return err
+	}
+	return writeRecord(e.writer, e.record, e.recordQuoting)
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (e *TypedEncoder[T]) Flush() error {
+	if err := e.writeHeader(reflect.Value{}); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// MarshalSeq writes every value yielded by seq as CSV, preceded by the header row.
+// Its signature matches iter.Seq[T], so range-over-func iterators can be passed directly.
+func MarshalSeq[T any](seq func(yield func(T) bool), out io.Writer, opts ...Option) error {
+	enc, err := NewEncoder[T](out, opts...)
+	if err != nil {
+		return err
+	}
+	var encodeErr error
+	seq(func(v T) bool {
+		encodeErr = enc.Encode(v)
+		return encodeErr == nil
+	})
+	if encodeErr != nil {
+		return encodeErr
+	}
+	return enc.Flush()
+}
+
+// UnmarshalSeq returns an iterator over the CSV records of the reader decoded
+// into values of type T, a struct or a pointer to a struct. Its signature
+// matches iter.Seq2[T, error], so it can be ranged over directly:
+//
+//	for client, err := range gocsv.UnmarshalSeq[Client](file) {
+//		...
+//	}
+//
+// A record that fails to decode is yielded with its error, and the iteration
+// goes on if the loop does. An error reading the input is yielded last.
+func UnmarshalSeq[T any](in io.Reader, opts ...Option) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		d, err := NewRowDecoder(in, opts...)
+		if err != nil {
+			var v T
+			yield(v, err)
+			return
+		}
+		DecodeSeq[T](d)(yield)
+	}
+}
+
+// DecodeSeq returns an iterator over the remaining records of d decoded into
+// values of type T, a struct or a pointer to a struct. Its signature matches
+// iter.Seq2[T, error] (see UnmarshalSeq).
+func DecodeSeq[T any](d *RowDecoder) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		for d.Next() {
+			v, err := decodeAs[T](d)
+			if !yield(v, err) {
+				return
+			}
+		}
+		if err := d.Err(); err != nil {
+			var v T
+			yield(v, err)
+		}
+	}
+}
+
+// decodeAs decodes the current record of d into a new value of type T.
+func decodeAs[T any](d *RowDecoder) (T, error) {
+	var v T
+	if t := reflect.TypeOf((*T)(nil)).Elem(); t.Kind() == reflect.Ptr {
+		p := reflect.New(t.Elem())
+		reflect.ValueOf(&v).Elem().Set(p)
+		return v, d.Decode(p.Interface())
+	}
+	return v, d.Decode(&v)
+}
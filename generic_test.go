@@ -0,0 +1,122 @@
+package gocsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalAs(t *testing.T) {
+	samples, err := UnmarshalAs[Sample](strings.NewReader(`foo,BAR,Baz,Quux
+f,1,baz,0.5
+e,3,b,`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 sample instances, got %d", len(samples))
+	}
+	expected := Sample{Foo: "f", Bar: 1, Baz: "baz", Frop: 0.5}
+	if !reflect.DeepEqual(expected, samples[0]) {
+		t.Fatalf("expected first sample %v, got %v", expected, samples[0])
+	}
+
+	if _, err := UnmarshalAs[int](strings.NewReader("foo\n1")); err == nil {
+		t.Fatal("expected an error for a non struct type")
+	}
+}
+
+func TestTypedDecoder(t *testing.T) {
+	d, err := NewDecoder[*Sample](strings.NewReader(`foo,BAR
+f,1
+e,BAD_INPUT
+g,3`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := d.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Foo != "f" || s.Bar != 1 {
+		t.Fatalf("unexpected first sample %+v", s)
+	}
+
+	_, err = d.Read()
+	parseErr, ok := err.(*csv.ParseError)
+	if !ok {
+		t.Fatalf("expected a *csv.ParseError, got %v", err)
+	}
+	if parseErr.Line != 3 || parseErr.Column != 2 {
+		t.Fatalf("expected error on line 3 column 2, got line %d column %d", parseErr.Line, parseErr.Column)
+	}
+
+	rest, err := d.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 || rest[0].Foo != "g" || rest[0].Bar != 3 {
+		t.Fatalf("unexpected remaining samples %+v", rest)
+	}
+	if _, err := d.Read(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+
+	if _, err := NewDecoder[Sample](strings.NewReader("")); err != ErrEmptyCSVFile {
+		t.Fatalf("expected ErrEmptyCSVFile, got %v", err)
+	}
+}
+
+func TestTypedEncoder(t *testing.T) {
+	b := bytes.Buffer{}
+	e, err := NewEncoder[MultiTagSample](&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Encode(MultiTagSample{Foo: "a", Bar: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Encode(MultiTagSample{Foo: "b", Bar: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Baz,BAR\na,1\nb,2\n"; b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+
+	if _, err := NewEncoder[string](&b); err == nil {
+		t.Fatal("expected an error for a non struct type")
+	}
+}
+
+func TestMarshalSeq(t *testing.T) {
+	seq := func(yield func(*MultiTagSample) bool) {
+		for i, foo := range []string{"a", "b", "c"} {
+			if !yield(&MultiTagSample{Foo: foo, Bar: i}) {
+				return
+			}
+		}
+	}
+	b := bytes.Buffer{}
+	if err := MarshalSeq(seq, &b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Baz,BAR\na,0\nb,1\nc,2\n"; b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	empty := func(yield func(MultiTagSample) bool) {}
+	if err := MarshalSeq(empty, &b); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Baz,BAR\n"; b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+}
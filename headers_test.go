@@ -0,0 +1,89 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalWithHeaders(t *testing.T) {
+	headers := []string{"BAR", "unknown", "foo"}
+	in := "1,x,a\n2,y,b"
+
+	var samples []Sample
+	if err := UnmarshalWithHeaders(strings.NewReader(in), headers, &samples); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Foo != "a" || samples[0].Bar != 1 || samples[1].Foo != "b" || samples[1].Bar != 2 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+
+	c := make(chan Sample)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- UnmarshalToChanWithHeaders(strings.NewReader(in), headers, c)
+	}()
+	count := 0
+	for range c {
+		count++
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 samples, got %d", count)
+	}
+
+	var foos []string
+	if err := UnmarshalToCallbackWithHeaders(strings.NewReader(in), headers, func(s *Sample) {
+		foos = append(foos, s.Foo)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(foos, ",") != "a,b" {
+		t.Fatalf("unexpected values %v", foos)
+	}
+
+	um, err := NewUnmarshallerWithHeaders(csv.NewReader(strings.NewReader(in)), headers, Sample{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := um.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := v.(Sample); s.Foo != "a" || s.Bar != 1 {
+		t.Fatalf("unexpected sample %+v", s)
+	}
+}
+
+func TestUnmarshalWithHeadersMatching(t *testing.T) {
+	// the first record is on line 1
+	var samples []Sample
+	err := UnmarshalWithHeaders(strings.NewReader("x,a"), []string{"BAR", "foo"}, &samples)
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 1 || parseErr.Column != 1 {
+		t.Fatalf("expected an error on line 1, column 1, got %v", err)
+	}
+
+	// the header normalizer applies to the given headers
+	err = UnmarshalWithHeaders(strings.NewReader("1,a"), []string{" bar ", " FOO "}, &samples, WithHeaderNormalizer(func(s string) string {
+		return strings.ToLower(strings.TrimSpace(s))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Foo != "a" || samples[0].Bar != 1 {
+		t.Fatalf("unexpected sample %+v", samples[0])
+	}
+
+	err = UnmarshalWithHeaders(strings.NewReader("1"), []string{"BAR"}, &samples, WithFailIfUnmatchedStructTags(true))
+	if !errors.Is(err, ErrUnmatchedStructTags) {
+		t.Fatalf("expected ErrUnmatchedStructTags, got %v", err)
+	}
+
+	if err := UnmarshalWithHeaders(strings.NewReader("1"), nil, &samples); err == nil {
+		t.Fatal("expected an error without headers")
+	}
+}
@@ -0,0 +1,273 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+var (
+	// ErrUnknownRecordCode is returned, wrapped in a *csv.ParseError, by
+	// MultiRecordDecoder for a record whose code was not registered.
+	ErrUnknownRecordCode = errors.New("unknown record code")
+
+	// ErrRecordOrder is returned, wrapped in a *csv.ParseError, by
+	// MultiRecordDecoder for a header, detail or trailer record out of place.
+	ErrRecordOrder = errors.New("record out of order")
+
+	// ErrTrailerMismatch is returned, wrapped in a *csv.ParseError, by
+	// MultiRecordDecoder when a trailer record does not match the detail
+	// records it closes.
+	ErrTrailerMismatch = errors.New("trailer does not match the detail records")
+)
+
+// RecordRole tells where a kind of record appears in a multi-record file.
+type RecordRole int
+
+const (
+	// HeaderRecord opens a group of detail records.
+	HeaderRecord RecordRole = iota
+	// DetailRecord holds the data.
+	DetailRecord
+	// TrailerRecord closes a group of detail records, usually with counts and totals.
+	TrailerRecord
+)
+
+func (r RecordRole) String() string {
+	switch r {
+	case HeaderRecord:
+		return "header"
+	case DetailRecord:
+		return "detail"
+	case TrailerRecord:
+		return "trailer"
+	}
+	return fmt.Sprintf("RecordRole(%d)", int(r))
+}
+
+type recordLayout struct {
+	role       RecordRole
+	wasPointer bool
+	columns    *columnLayout
+}
+
+type trailerSumCheck struct {
+	trailerField string
+	detailField  string
+}
+
+// MultiRecordDecoder decodes files without a header row made of several kinds
+// of records, e.g. bank or EDI files with a header record, detail records and
+// a trailer record. Each kind of record has its own layout, and is told apart
+// by the record code held in column CodeColumn. The columns of a record,
+// including the record code, are mapped by position to the fields of the
+// struct registered for its code.
+//
+// Header and trailer records are optional. When registered, a header record
+// must open each group of detail records, and a trailer record must close it,
+// after which a new group may start. Trailer records can be checked against
+// the detail records of their group with CheckTrailerCount and CheckTrailerSum.
+type MultiRecordDecoder struct {
+	// CodeColumn is the index of the column holding the record code, 0 by default.
+	CodeColumn int
+
+	cfg         *Config
+	decoder     SimpleDecoder
+	layouts     map[string]*recordLayout
+	hasHeader   bool
+	hasTrailer  bool
+	countField  string
+	sumChecks   []trailerSumCheck
+	line        int
+	inGroup     bool // a group was opened by a header or detail record, and not closed by a trailer
+	detailCount int
+	sums        map[string]float64
+}
+
+// NewMultiRecordDecoder creates a MultiRecordDecoder reading the records of in.
+func NewMultiRecordDecoder(in SimpleDecoder, opts ...Option) *MultiRecordDecoder {
+	return &MultiRecordDecoder{
+		cfg:     newConfig(opts),
+		decoder: in,
+		layouts: make(map[string]*recordLayout),
+		sums:    make(map[string]float64),
+	}
+}
+
+// Register decodes the records with the given code into the type of
+// prototype, a struct or a pointer to a struct, e.g. Payment{} or &Payment{}.
+func (d *MultiRecordDecoder) Register(code string, role RecordRole, prototype interface{}) error {
+	t := reflect.TypeOf(prototype)
+	if t == nil {
+		return fmt.Errorf("cannot register a nil prototype for record code %q", code)
+	}
+	wasPointer := t.Kind() == reflect.Ptr
+	if wasPointer {
+		t = t.Elem()
+	}
+	if err := ensureOutInnerType(t); err != nil {
+		return err
+	}
+	info := getStructInfo(d.cfg, t)
+	if len(info.Fields) == 0 {
+		return ErrNoStructTags
+	}
+	columns, err := newColumnLayout(d.cfg, t, info)
+	if err != nil {
+		return err
+	}
+	d.layouts[code] = &recordLayout{role: role, wasPointer: wasPointer, columns: columns}
+	switch role {
+	case HeaderRecord:
+		d.hasHeader = true
+	case TrailerRecord:
+		d.hasTrailer = true
+	}
+	return nil
+}
+
+// CheckTrailerCount makes each trailer record check that its field named
+// trailerField holds the number of detail records of its group.
+func (d *MultiRecordDecoder) CheckTrailerCount(trailerField string) {
+	d.countField = trailerField
+}
+
+// CheckTrailerSum makes each trailer record check that its field named
+// trailerField holds the sum of the fields named detailField of the detail
+// records of its group.
+func (d *MultiRecordDecoder) CheckTrailerSum(trailerField, detailField string) {
+	d.sumChecks = append(d.sumChecks, trailerSumCheck{trailerField, detailField})
+}
+
+// Line returns the line of the last record read, starting at 1.
+func (d *MultiRecordDecoder) Line() int {
+	return d.line
+}
+
+// Read decodes the next record, and returns it with its role. It returns
+// io.EOF once every record was read.
+func (d *MultiRecordDecoder) Read() (interface{}, RecordRole, error) {
+	record, err := d.decoder.GetCSVRow()
+	if err == io.EOF {
+		if d.inGroup && d.hasTrailer {
+			return nil, 0, &csv.ParseError{Line: d.line + 1, Err: fmt.Errorf("%w: missing trailer record", ErrRecordOrder)}
+		}
+		return nil, 0, io.EOF
+	} else if err != nil {
+		return nil, 0, err
+	}
+	d.line++
+
+	var code string
+	if d.CodeColumn < len(record) {
+		code = record[d.CodeColumn]
+	}
+	layout, ok := d.layouts[code]
+	if !ok {
+		return nil, 0, &csv.ParseError{Line: d.line, Column: d.CodeColumn + 1, Err: fmt.Errorf("%w %q", ErrUnknownRecordCode, code)}
+	}
+	if err := d.checkOrder(layout.role); err != nil {
+		return nil, 0, &csv.ParseError{Line: d.line, Column: d.CodeColumn + 1, Err: err}
+	}
+
+	outInner, err := convertWithoutHeaders(record, d.line, layout.wasPointer, layout.columns, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch layout.role {
+	case HeaderRecord:
+		d.inGroup = true
+		d.detailCount = 0
+		d.sums = make(map[string]float64)
+	case DetailRecord:
+		d.inGroup = true
+		d.detailCount++
+		for _, check := range d.sumChecks {
+			if n, ok := getNumberField(outInner, check.detailField); ok {
+				d.sums[check.detailField] += n
+			}
+		}
+	case TrailerRecord:
+		if err := d.checkTrailer(outInner); err != nil {
+			return nil, 0, &csv.ParseError{Line: d.line, Err: err}
+		}
+		d.inGroup = false
+		d.detailCount = 0
+		d.sums = make(map[string]float64)
+	}
+	return outInner.Interface(), layout.role, nil
+}
+
+// checkOrder checks that a record with the given role may come now.
+func (d *MultiRecordDecoder) checkOrder(role RecordRole) error {
+	switch role {
+	case HeaderRecord:
+		if d.inGroup && d.hasTrailer {
+			return fmt.Errorf("%w: header record before the trailer of the previous group", ErrRecordOrder)
+		}
+	case DetailRecord:
+		if d.hasHeader && !d.inGroup {
+			return fmt.Errorf("%w: detail record before a header record", ErrRecordOrder)
+		}
+	case TrailerRecord:
+		if d.hasHeader && !d.inGroup {
+			return fmt.Errorf("%w: trailer record before a header record", ErrRecordOrder)
+		}
+	}
+	return nil
+}
+
+// checkTrailer checks the trailer record against the detail records of its group.
+func (d *MultiRecordDecoder) checkTrailer(trailer reflect.Value) error {
+	if d.countField != "" {
+		count, ok := getNumberField(trailer, d.countField)
+		if !ok {
+			return fmt.Errorf("trailer has no numeric field %s", d.countField)
+		}
+		if count != float64(d.detailCount) {
+			return fmt.Errorf("%w: %s is %v, but there are %d detail records", ErrTrailerMismatch, d.countField, count, d.detailCount)
+		}
+	}
+	for _, check := range d.sumChecks {
+		total, ok := getNumberField(trailer, check.trailerField)
+		if !ok {
+			return fmt.Errorf("trailer has no numeric field %s", check.trailerField)
+		}
+		sum := d.sums[check.detailField]
+		// allow for the rounding errors of float sums
+		if math.Abs(total-sum) > 1e-9*math.Max(1, math.Abs(total)) {
+			return fmt.Errorf("%w: %s is %v, but the sum of %s is %v", ErrTrailerMismatch, check.trailerField, total, check.detailField, sum)
+		}
+	}
+	return nil
+}
+
+// getNumberField returns the value of the numeric field called name of the struct v.
+func getNumberField(v reflect.Value, name string) (float64, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+	field := v.FieldByName(name)
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return 0, true
+		}
+		field = field.Elem()
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,135 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type batchHeader struct {
+	Code  string `csv:"code"`
+	Batch string `csv:"batch"`
+}
+
+type batchPayment struct {
+	Code    string  `csv:"code"`
+	Account string  `csv:"account"`
+	Amount  float64 `csv:"amount"`
+}
+
+type batchFee struct {
+	Code   string  `csv:"code"`
+	Amount float64 `csv:"amount"`
+}
+
+type batchTrailer struct {
+	Code  string  `csv:"code"`
+	Count int     `csv:"count"`
+	Total float64 `csv:"total"`
+}
+
+func newBatchDecoder(t *testing.T, in string) *MultiRecordDecoder {
+	r := csv.NewReader(strings.NewReader(in))
+	r.FieldsPerRecord = -1
+	d := NewMultiRecordDecoder(NewSimpleDecoderFromCSVReader(r))
+	for code, layout := range map[string]struct {
+		role      RecordRole
+		prototype interface{}
+	}{
+		"H": {HeaderRecord, batchHeader{}},
+		"D": {DetailRecord, &batchPayment{}},
+		"F": {DetailRecord, batchFee{}},
+		"T": {TrailerRecord, batchTrailer{}},
+	} {
+		if err := d.Register(code, layout.role, layout.prototype); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.CheckTrailerCount("Count")
+	d.CheckTrailerSum("Total", "Amount")
+	return d
+}
+
+func readAllRecords(d *MultiRecordDecoder) ([]interface{}, []RecordRole, error) {
+	var records []interface{}
+	var roles []RecordRole
+	for {
+		record, role, err := d.Read()
+		if err == io.EOF {
+			return records, roles, nil
+		} else if err != nil {
+			return records, roles, err
+		}
+		records = append(records, record)
+		roles = append(roles, role)
+	}
+}
+
+func TestMultiRecordDecoder(t *testing.T) {
+	in := `H,B1
+D,FR76,10.5
+F,0.25
+D,DE89,4
+T,3,14.75
+H,B2
+T,0,0`
+	records, roles, err := readAllRecords(newBatchDecoder(t, in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		batchHeader{"H", "B1"},
+		&batchPayment{"D", "FR76", 10.5},
+		batchFee{"F", 0.25},
+		&batchPayment{"D", "DE89", 4},
+		batchTrailer{"T", 3, 14.75},
+		batchHeader{"H", "B2"},
+		batchTrailer{"T", 0, 0},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, records)
+	}
+	expectedRoles := []RecordRole{HeaderRecord, DetailRecord, DetailRecord, DetailRecord, TrailerRecord, HeaderRecord, TrailerRecord}
+	if !reflect.DeepEqual(roles, expectedRoles) {
+		t.Fatalf("expected roles %v, got %v", expectedRoles, roles)
+	}
+}
+
+func TestMultiRecordDecoderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		err  error
+		line int
+	}{
+		{"unknown code", "H,B1\nX,1", ErrUnknownRecordCode, 2},
+		{"detail before header", "D,FR76,1\nT,1,1", ErrRecordOrder, 1},
+		{"missing trailer", "H,B1\nD,FR76,1", ErrRecordOrder, 3},
+		{"header in group", "H,B1\nD,FR76,1\nH,B2", ErrRecordOrder, 3},
+		{"wrong count", "H,B1\nD,FR76,1\nT,2,1", ErrTrailerMismatch, 3},
+		{"wrong sum", "H,B1\nD,FR76,1\nD,DE89,2\nT,2,4", ErrTrailerMismatch, 4},
+		{"too many fields", "H,B1,extra", csv.ErrFieldCount, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := readAllRecords(newBatchDecoder(t, test.in))
+			var parseErr *csv.ParseError
+			if !errors.Is(err, test.err) || !errors.As(err, &parseErr) || parseErr.Line != test.line {
+				t.Fatalf("expected %v on line %d, got %v", test.err, test.line, err)
+			}
+		})
+	}
+}
+
+func TestMultiRecordDecoderRegisterErrors(t *testing.T) {
+	d := NewMultiRecordDecoder(NewSimpleDecoderFromCSVReader(csv.NewReader(strings.NewReader(""))))
+	if err := d.Register("X", DetailRecord, 1); err == nil {
+		t.Fatal("expected an error for a non-struct prototype")
+	}
+	if err := d.Register("X", DetailRecord, nil); err == nil {
+		t.Fatal("expected an error for a nil prototype")
+	}
+}
@@ -0,0 +1,162 @@
+package gocsv
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+// recordsPerBatch is the number of records handed at once to a worker, so
+// that the cost of the channel operations is shared by the batch.
+const recordsPerBatch = 64
+
+// batchesPerWorker bounds the number of batches read ahead of the output for
+// each worker, so that a slow consumer does not make the whole input pile up
+// in memory.
+const batchesPerWorker = 2
+
+// decodeBatch is a batch of records read by the reader goroutine of
+// convertRecords, with their results once decoded by a worker.
+type decodeBatch struct {
+	seq      int
+	first    int // Index of the first record
+	records  [][]string
+	readErr  error // Error returned by the row reader after the records, io.EOF at the end
+	values   []reflect.Value
+	failures [][]cellFailure
+	err      error // Error stopping the decoding after the values
+}
+
+// convertRecords reads the records returned by nextRow until io.EOF, converts
+// them and gives them in input order to store, along with their index. The
+// first record is found at line firstLine.
+//
+// With Config.Workers above 1, one goroutine reads the records while the
+// workers convert them. Errors are still reported in input order: the error
+// handler and collect mode see the failures of a record once every previous
+// record was stored, and the first error returned stops the reading. The
+// records are copied before being handed to the workers, as the reader may
+// reuse them (see csv.Reader.ReuseRecord), and are handed in batches of
+// recordsPerBatch: a record is converted once its batch is full or the input
+// ends.
+func convertRecords(cfg *Config, converter *rowConverter, nextRow func() ([]string, error), firstLine int, store func(i int, v reflect.Value) error) error {
+	if cfg.Workers <= 1 {
+		for i := 0; ; i++ {
+			record, err := nextRow()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			outInner, err := converter.convert(record, firstLine+i)
+			if err != nil {
+				return err
+			}
+			if err := store(i, outInner); err != nil {
+				return err
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	defer func() {
+		close(done) // stop the reader and the workers on early return
+		wg.Wait()
+	}()
+
+	// tokens bounds the batches in flight, from their reading to their storing
+	tokens := make(chan struct{}, cfg.Workers*batchesPerWorker)
+	batches := make(chan *decodeBatch)
+	results := make(chan *decodeBatch)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(batches)
+		for seq, first := 0, 0; ; seq++ {
+			select {
+			case tokens <- struct{}{}:
+			case <-done:
+				return
+			}
+			batch := &decodeBatch{seq: seq, first: first, records: make([][]string, 0, recordsPerBatch)}
+			for len(batch.records) < recordsPerBatch {
+				record, err := nextRow()
+				if err != nil {
+					batch.readErr = err
+					break
+				}
+				batch.records = append(batch.records, append([]string(nil), record...))
+			}
+			first += len(batch.records)
+			select {
+			case batches <- batch:
+			case <-done:
+				return
+			}
+			if batch.readErr != nil {
+				return
+			}
+		}
+	}()
+
+	var workers sync.WaitGroup
+	workers.Add(cfg.Workers)
+	for w := 0; w < cfg.Workers; w++ {
+		go func() {
+			defer workers.Done()
+			for batch := range batches {
+				for k, record := range batch.records {
+					value, failures, err := converter.decode(record, firstLine+batch.first+k)
+					if err != nil {
+						batch.err = err
+						break
+					}
+					batch.values = append(batch.values, value)
+					batch.failures = append(batch.failures, failures)
+				}
+				select {
+				case results <- batch:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		workers.Wait()
+		close(results)
+	}()
+
+	// Results arrive in any order: hold them until their turn comes
+	pending := make(map[int]*decodeBatch)
+	next := 0
+	for result := range results {
+		pending[result.seq] = result
+		for batch, ok := pending[next]; ok; batch, ok = pending[next] {
+			delete(pending, next)
+			for k, value := range batch.values {
+				if err := converter.handleFailures(batch.failures[k]); err != nil {
+					return err
+				}
+				if err := store(batch.first+k, value); err != nil {
+					return err
+				}
+			}
+			if batch.err != nil {
+				return batch.err
+			}
+			if batch.readErr == io.EOF {
+				return nil
+			} else if batch.readErr != nil {
+				return batch.readErr
+			}
+			next++
+			<-tokens
+		}
+	}
+	return nil
+}
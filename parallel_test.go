@@ -0,0 +1,145 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type parallelSample struct {
+	ID   int    `csv:"id"`
+	Name string `csv:"name"`
+}
+
+func parallelInput(rows int, badLine int) string {
+	var b strings.Builder
+	b.WriteString("id,name\n")
+	for i := 0; i < rows; i++ {
+		if i+2 == badLine {
+			fmt.Fprintf(&b, "oops,name%d\n", i)
+			continue
+		}
+		fmt.Fprintf(&b, "%d,name%d\n", i, i)
+	}
+	return b.String()
+}
+
+func TestWorkersKeepOrder(t *testing.T) {
+	in := parallelInput(5000, 0)
+	var samples []parallelSample
+	if err := UnmarshalString(in, &samples, WithWorkers(8)); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 5000 {
+		t.Fatalf("expected 5000 samples, got %d", len(samples))
+	}
+	for i, s := range samples {
+		if s.ID != i || s.Name != fmt.Sprintf("name%d", i) {
+			t.Fatalf("sample %d out of order: %+v", i, s)
+		}
+	}
+
+	c := make(chan parallelSample)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- UnmarshalToChan(strings.NewReader(in), c, WithWorkers(8))
+	}()
+	i := 0
+	for s := range c {
+		if s.ID != i {
+			t.Fatalf("sample %d out of order: %+v", i, s)
+		}
+		i++
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if i != 5000 {
+		t.Fatalf("expected 5000 samples, got %d", i)
+	}
+}
+
+func TestWorkersReuseRecord(t *testing.T) {
+	reader := csv.NewReader(strings.NewReader(parallelInput(5000, 0)))
+	reader.ReuseRecord = true
+	var samples []parallelSample
+	if err := UnmarshalCSV(reader, &samples, WithWorkers(8)); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range samples {
+		if s.ID != i || s.Name != fmt.Sprintf("name%d", i) {
+			t.Fatalf("sample %d mixed with another record: %+v", i, s)
+		}
+	}
+}
+
+func TestWorkersErrorLine(t *testing.T) {
+	in := parallelInput(1000, 700)
+	var samples []parallelSample
+	err := UnmarshalString(in, &samples, WithWorkers(4))
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a *csv.ParseError, got %v", err)
+	}
+	if parseErr.Line != 700 || parseErr.Column != 1 {
+		t.Fatalf("expected error at line 700, column 1, got line %d, column %d", parseErr.Line, parseErr.Column)
+	}
+
+	// collect mode lists the errors in input order
+	in = parallelInput(1000, 300) + "bad,x\n"
+	err = UnmarshalString(in, &samples, WithWorkers(4), WithCollectErrors(0))
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) {
+		t.Fatalf("expected *DecodeErrors, got %v", err)
+	}
+	if len(decodeErrs.Errors) != 2 || decodeErrs.Errors[0].Line != 300 || decodeErrs.Errors[1].Line != 1002 {
+		t.Fatalf("unexpected errors: %v", decodeErrs)
+	}
+	if len(samples) != 1001 || samples[999].ID != 999 {
+		t.Fatalf("unexpected samples: %d", len(samples))
+	}
+
+	// the error handler is called in input order too
+	var lines []int
+	err = UnmarshalWithErrorHandler(strings.NewReader(in), func(err *csv.ParseError) bool {
+		lines = append(lines, err.Line)
+		return true
+	}, &samples, WithWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 || lines[0] != 300 || lines[1] != 1002 {
+		t.Fatalf("unexpected error lines: %v", lines)
+	}
+}
+
+func TestWorkersStopOnError(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		var samples []parallelSample
+		if err := UnmarshalString(parallelInput(2000, 10), &samples, WithWorkers(4)); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	// every goroutine is done once the call returns
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("goroutines leaked: %d before, %d after", before, after)
+	}
+}
+
+func BenchmarkUnmarshalWorkers(b *testing.B) {
+	in := parallelInput(10000, 0)
+	for _, workers := range []int{1, 4, 8, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var samples []parallelSample
+				if err := UnmarshalString(in, &samples, WithWorkers(workers)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// ErrUnknownDiscriminator is returned, wrapped in a *csv.ParseError, when the
+// discriminator column of a row holds a value that was not registered.
+var ErrUnknownDiscriminator = errors.New("unknown discriminator value")
+
+// Discriminator maps the values of a column, e.g. "type", to the struct types
+// of the rows of a file mixing several kinds of records. It is used by
+// UnmarshalPolymorphic, UnmarshalPolymorphicToChan and MarshalPolymorphic.
+type Discriminator struct {
+	Column string
+	types  map[string]reflect.Type
+	values map[reflect.Type]string
+}
+
+// NewDiscriminator creates a Discriminator reading the type of each row in column.
+func NewDiscriminator(column string) *Discriminator {
+	return &Discriminator{
+		Column: column,
+		types:  make(map[string]reflect.Type),
+		values: make(map[reflect.Type]string),
+	}
+}
+
+// Register makes the rows whose discriminator column holds value decode into
+// the type of prototype, a struct or a pointer to a struct, e.g. Payment{} or
+// &Payment{}.
+func (d *Discriminator) Register(value string, prototype interface{}) error {
+	t := reflect.TypeOf(prototype)
+	if t == nil || ensureStructOrPtr(t) != nil || (t.Kind() == reflect.Ptr && t.Elem().Kind() != reflect.Struct) {
+		return fmt.Errorf("cannot register %T for discriminator value %q, only struct or pointer to struct supported", prototype, value)
+	}
+	d.types[value] = t
+	d.values[t] = value
+	if t.Kind() == reflect.Ptr {
+		d.values[t.Elem()] = value
+	} else {
+		d.values[reflect.PtrTo(t)] = value
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics if prototype is neither a struct
+// nor a pointer to a struct. It returns d, so that calls can be chained.
+func (d *Discriminator) MustRegister(value string, prototype interface{}) *Discriminator {
+	if err := d.Register(value, prototype); err != nil {
+		panic("gocsv: " + err.Error())
+	}
+	return d
+}
+
+// polymorphicConverter converts CSV records into the type registered for their
+// discriminator value, with one rowConverter per type.
+type polymorphicConverter struct {
+	cfg        *Config
+	d          *Discriminator
+	headers    []string // Normalized headers
+	rawHeaders []string // Headers as read
+	column     int
+	errHandler ErrorHandler
+	converters map[reflect.Type]*rowConverter
+	collector  *errorCollector // Set in collect mode, shared by the converters
+}
+
+func newPolymorphicConverter(cfg *Config, d *Discriminator, rawHeaders []string, errHandler ErrorHandler) (*polymorphicConverter, error) {
+	headers := normalizeHeaders(cfg, rawHeaders)
+	column := -1
+	for i, header := range headers {
+		if header == cfg.normalize(d.Column) {
+			column = i
+			break
+		}
+	}
+	if column < 0 {
+		return nil, fmt.Errorf("discriminator column %q not found in header", d.Column)
+	}
+	p := &polymorphicConverter{
+		cfg:        cfg,
+		d:          d,
+		headers:    headers,
+		rawHeaders: rawHeaders,
+		column:     column,
+		errHandler: errHandler,
+		converters: make(map[reflect.Type]*rowConverter),
+	}
+	if cfg.CollectErrors {
+		p.collector = &errorCollector{max: cfg.MaxErrors}
+	}
+	return p, nil
+}
+
+// convert builds a value of the type registered for the discriminator value
+// of the record found at the given line. ok is false when the record was
+// skipped: its discriminator value is unknown and the error was handled or
+// collected.
+func (p *polymorphicConverter) convert(record []string, line int) (value reflect.Value, ok bool, err error) {
+	var discriminator string
+	if p.column < len(record) {
+		discriminator = record[p.column]
+	}
+	t, found := p.d.types[discriminator]
+	if !found {
+		parseError := &csv.ParseError{
+			Line:   line,
+			Column: p.column + 1,
+			Err:    fmt.Errorf("%w %q", ErrUnknownDiscriminator, discriminator),
+		}
+		if p.errHandler != nil && p.errHandler(parseError) {
+			return reflect.Value{}, false, nil
+		}
+		if p.collector == nil {
+			return reflect.Value{}, false, parseError
+		}
+		if !p.collector.add(&DecodeError{Line: line, Column: p.column + 1, Header: p.headers[p.column], Value: discriminator, Err: parseError.Err}) {
+			return reflect.Value{}, false, p.collector.err()
+		}
+		return reflect.Value{}, false, nil
+	}
+
+	converter, found := p.converters[t]
+	if !found {
+		converter, err = newRowConverter(p.cfg, t, p.rawHeaders, p.errHandler)
+		if err != nil {
+			return reflect.Value{}, false, err
+		}
+		converter.collector = p.collector
+		p.converters[t] = converter
+	}
+	value, err = converter.convert(record, line)
+	return value, err == nil, err
+}
+
+func (p *polymorphicConverter) collectedErrors() error {
+	if p.collector == nil {
+		return nil
+	}
+	return p.collector.err()
+}
+
+// UnmarshalPolymorphic parses the CSV from the reader into out, a pointer to a
+// slice of an interface type implemented by the registered types, e.g. *[]Event.
+// Each row is decoded into the type registered for the value of its
+// discriminator column.
+func UnmarshalPolymorphic(in io.Reader, d *Discriminator, out interface{}, opts ...Option) error {
+	cfg := newConfig(opts)
+	outValue, outType := getConcreteReflectValueAndType(out)
+	if outType.Kind() != reflect.Slice {
+		return fmt.Errorf("cannot use " + outType.String() + ", only slice supported")
+	}
+	return readPolymorphic(cfg, newSimpleDecoderFromReader(cfg, in), d, outType.Elem(), func(i int, v reflect.Value) error {
+		return storeOutInner(outValue, i, v)
+	})
+}
+
+// UnmarshalPolymorphicToChan parses the CSV from the reader and sends each row
+// to c, a channel of an interface type implemented by the registered types.
+// The channel is closed once the input is read.
+func UnmarshalPolymorphicToChan(in io.Reader, d *Discriminator, c interface{}, opts ...Option) error {
+	cfg := newConfig(opts)
+	outValue, outType := getConcreteReflectValueAndType(c)
+	if outType.Kind() != reflect.Chan {
+		return fmt.Errorf("cannot use %v with type %s, only channel supported", c, outType)
+	}
+	defer outValue.Close()
+	return readPolymorphic(cfg, newSimpleDecoderFromReader(cfg, in), d, outType.Elem(), func(_ int, v reflect.Value) error {
+		outValue.Send(v)
+		return nil
+	})
+}
+
+func readPolymorphic(cfg *Config, decoder SimpleDecoder, d *Discriminator, elemType reflect.Type, store func(i int, v reflect.Value) error) error {
+	headers, err := decoder.GetCSVRow()
+	if err == io.EOF {
+		return ErrEmptyCSVFile
+	} else if err != nil {
+		return err
+	}
+	converter, err := newPolymorphicConverter(cfg, d, headers, nil)
+	if err != nil {
+		return err
+	}
+
+	i := 0
+	for line := 2 + preambleLines(decoder); ; line++ { // start at 2 to account for the header
+		record, err := decoder.GetCSVRow()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		value, ok, err := converter.convert(record, line)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if !value.Type().AssignableTo(elemType) {
+			return fmt.Errorf("cannot use %s as %s", value.Type(), elemType)
+		}
+		if err := store(i, value); err != nil {
+			return err
+		}
+		i++
+	}
+	return converter.collectedErrors()
+}
+
+// MarshalPolymorphic writes in, a slice or array of values of the registered
+// types, typically held by an interface type, as CSV. The header is the
+// discriminator column followed by the union of the columns of the types, in
+// order of appearance, including the columns of their dynamic slices and maps
+// and of their remain fields. The discriminator column holds the registered
+// value of each type, unless the type has a field mapped to it.
+func MarshalPolymorphic(in interface{}, out io.Writer, d *Discriminator, opts ...Option) error {
+	cfg := newConfig(opts)
+	writer := cfg.getCSVWriter(out)
+	inValue, inType := getConcreteReflectValueAndType(in)
+	if err := ensureInType(inType); err != nil {
+		return err
+	}
+
+	// the columns of dynamic slices and remain fields are those of the values of each type
+	var types []reflect.Type
+	elems := make(map[reflect.Type][]reflect.Value)
+	for i := 0; i < inValue.Len(); i++ {
+		elem, _, inInnerType, err := getPolymorphicElem(inValue, i)
+		if err != nil {
+			return err
+		}
+		if _, found := elems[inInnerType]; !found {
+			types = append(types, inInnerType)
+		}
+		elems[inInnerType] = append(elems[inInnerType], elem)
+	}
+
+	header := []string{cfg.normalize(d.Column)}
+	headerQuoting := []QuotePolicy{cellQuoting(cfg.Quoting, stringType)}
+	minimal := cfg.Quoting == QuoteMinimal
+	positions := map[string]int{header[0]: 0}
+	addColumn := func(key string, policy QuotePolicy) {
+		if _, found := positions[key]; !found {
+			positions[key] = len(header)
+			header = append(header, key)
+			headerQuoting = append(headerQuoting, cellQuoting(policy, stringType))
+		}
+	}
+	infos := make(map[reflect.Type]*structInfo)
+	remainKeys := make(map[reflect.Type][]string)
+	for _, inInnerType := range types {
+		values := elems[inInnerType]
+		value := func(i int) reflect.Value { return values[i] }
+		info := expandDynamicFieldsForValues(cfg, getStructInfo(cfg, inInnerType), len(values), value)
+		infos[inInnerType] = info
+		remainKeys[inInnerType] = getRemainKeys(info, len(values), value)
+		for i, fieldInfo := range info.Fields {
+			addColumn(fieldInfo.getFirstKey(), cfg.quotePolicy(&info.Fields[i]))
+			minimal = minimal && cfg.quotePolicy(&info.Fields[i]) == QuoteMinimal
+		}
+		for _, key := range remainKeys[inInnerType] {
+			addColumn(key, cfg.Quoting)
+		}
+	}
+
+	// the quoting of the records depends on their type
+	quotings := make(map[reflect.Type][]QuotePolicy)
+	if minimal {
+		headerQuoting = nil
+	} else {
+		for inInnerType, info := range infos {
+			quoting := make([]QuotePolicy, len(header))
+			for j := range quoting {
+				quoting[j] = cellQuoting(cfg.Quoting, stringType)
+			}
+			for i, fieldInfo := range info.Fields {
+				quoting[positions[fieldInfo.getFirstKey()]] = cellQuoting(cfg.quotePolicy(&info.Fields[i]), columnType(inInnerType, &info.Fields[i]))
+			}
+			quotings[inInnerType] = quoting
+		}
+	}
+
+	if err := writeRecord(writer, header, headerQuoting); err != nil {
+		return err
+	}
+	record := make([]string, len(header))
+	for i := 0; i < inValue.Len(); i++ {
+		elem, inInnerWasPointer, inInnerType, err := getPolymorphicElem(inValue, i)
+		if err != nil {
+			return err
+		}
+		for j := range record {
+			record[j] = ""
+		}
+		value, found := d.values[inInnerType]
+		if !found {
+			return fmt.Errorf("type %s is not registered in the discriminator", inInnerType)
+		}
+		record[0] = value
+		info := infos[inInnerType]
+		for _, fieldInfo := range info.Fields {
+			fieldValue, err := getInnerField(elem, inInnerWasPointer, fieldInfo.IndexChain, &fieldInfo)
+			if err != nil {
+				return err
+			}
+			record[positions[fieldInfo.getFirstKey()]] = fieldValue
+		}
+		keys := remainKeys[inInnerType]
+		remain := make([]string, len(keys))
+		if err := fillRemainRecord(remain, elem, info, keys); err != nil {
+			return err
+		}
+		for j, key := range keys {
+			record[positions[key]] = remain[j]
+		}
+		if err := writeRecord(writer, record, quotings[inInnerType]); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// getPolymorphicElem returns the concrete value at index i of in, and its struct type.
+func getPolymorphicElem(in reflect.Value, i int) (elem reflect.Value, wasPointer bool, structType reflect.Type, err error) {
+	elem = in.Index(i)
+	for elem.Kind() == reflect.Interface {
+		if elem.IsNil() {
+			return elem, false, nil, fmt.Errorf("cannot marshal the nil value at index %d", i)
+		}
+		elem = elem.Elem()
+	}
+	structType = elem.Type()
+	if structType.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return elem, false, nil, fmt.Errorf("cannot marshal the nil value at index %d", i)
+		}
+		wasPointer, structType = true, structType.Elem()
+	}
+	if err := ensureInInnerType(structType); err != nil {
+		return elem, false, nil, err
+	}
+	return elem, wasPointer, structType, nil
+}
@@ -0,0 +1,167 @@
+package gocsv
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type polyEvent interface {
+	eventID() string
+}
+
+type polyPayment struct {
+	ID     string  `csv:"id"`
+	Amount float64 `csv:"amount"`
+}
+
+type polyRefund struct {
+	ID     string  `csv:"id"`
+	Amount float64 `csv:"amount"`
+	Reason string  `csv:"reason"`
+}
+
+type polyFee struct {
+	Type string `csv:"type"`
+	ID   string `csv:"id"`
+	Code string `csv:"code"`
+}
+
+func (p polyPayment) eventID() string { return p.ID }
+func (r *polyRefund) eventID() string { return r.ID }
+func (f polyFee) eventID() string     { return f.ID }
+
+func newPolyDiscriminator() *Discriminator {
+	return NewDiscriminator("type").
+		MustRegister("payment", polyPayment{}).
+		MustRegister("refund", &polyRefund{}).
+		MustRegister("fee", polyFee{})
+}
+
+func TestUnmarshalPolymorphic(t *testing.T) {
+	in := `type,id,amount,reason,code
+payment,p1,10.5,,
+refund,r1,3,damaged,
+fee,f1,,,F9`
+	var events []polyEvent
+	if err := UnmarshalPolymorphic(strings.NewReader(in), newPolyDiscriminator(), &events); err != nil {
+		t.Fatal(err)
+	}
+	expected := []polyEvent{
+		polyPayment{ID: "p1", Amount: 10.5},
+		&polyRefund{ID: "r1", Amount: 3, Reason: "damaged"},
+		polyFee{Type: "fee", ID: "f1", Code: "F9"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, events)
+	}
+
+	c := make(chan polyEvent)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- UnmarshalPolymorphicToChan(strings.NewReader(in), newPolyDiscriminator(), c)
+	}()
+	var fromChan []polyEvent
+	for e := range c {
+		fromChan = append(fromChan, e)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromChan, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, fromChan)
+	}
+}
+
+func TestUnmarshalPolymorphicErrors(t *testing.T) {
+	var events []polyEvent
+	err := UnmarshalPolymorphic(strings.NewReader("type,id\npayment,p1\nbonus,b1"), newPolyDiscriminator(), &events)
+	if !errors.Is(err, ErrUnknownDiscriminator) || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected an unknown discriminator error on line 3, got %v", err)
+	}
+
+	events = nil
+	err = UnmarshalPolymorphic(strings.NewReader("type,id,amount\nbonus,b1,\npayment,p1,x\nrefund,r1,2"), newPolyDiscriminator(), &events, WithCollectErrors(0))
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs.Errors) != 2 {
+		t.Fatalf("expected 2 collected errors, got %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected the rows of known types to be decoded, got %+v", events)
+	}
+
+	err = UnmarshalPolymorphic(strings.NewReader("kind,id\npayment,p1"), newPolyDiscriminator(), &events)
+	if err == nil || !strings.Contains(err.Error(), "discriminator column") {
+		t.Fatalf("expected a missing column error, got %v", err)
+	}
+}
+
+func TestMarshalPolymorphic(t *testing.T) {
+	events := []polyEvent{
+		polyPayment{ID: "p1", Amount: 10.5},
+		&polyRefund{ID: "r1", Amount: 3, Reason: "damaged"},
+		polyFee{Type: "fee", ID: "f1", Code: "F9"},
+	}
+	b := bytes.Buffer{}
+	if err := MarshalPolymorphic(events, &b, newPolyDiscriminator()); err != nil {
+		t.Fatal(err)
+	}
+	expected := `type,id,amount,reason,code
+payment,p1,10.5,,
+refund,r1,3,damaged,
+fee,f1,,,F9
+`
+	if b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+
+	if err := MarshalPolymorphic([]polyEvent{nil}, &b, newPolyDiscriminator()); err == nil {
+		t.Fatal("expected an error for a nil value")
+	}
+}
+
+type polyTagged struct {
+	ID    string            `csv:"id"`
+	Tags  []string          `csv:"tags" csv[]:"*"`
+	Extra map[string]string `csv:",remain"`
+}
+
+func (p polyTagged) eventID() string { return p.ID }
+
+func TestMarshalPolymorphicDynamicColumns(t *testing.T) {
+	d := newPolyDiscriminator().MustRegister("tagged", polyTagged{})
+	events := []polyEvent{
+		polyTagged{ID: "t1", Tags: []string{"a"}, Extra: map[string]string{"note": "x"}},
+		polyPayment{ID: "p1", Amount: 1},
+		polyTagged{ID: "t2", Tags: []string{"b", "c"}, Extra: map[string]string{"batch": "7"}},
+	}
+	b := bytes.Buffer{}
+	if err := MarshalPolymorphic(events, &b, d); err != nil {
+		t.Fatal(err)
+	}
+	expected := `type,id,tags[0],tags[1],batch,note,amount
+tagged,t1,a,,,x,
+payment,p1,,,,,1
+tagged,t2,b,c,7,,
+`
+	if b.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestDiscriminatorRegisterErrors(t *testing.T) {
+	d := NewDiscriminator("type")
+	for _, prototype := range []interface{}{nil, 1, new(int)} {
+		if err := d.Register("x", prototype); err == nil {
+			t.Fatalf("expected an error registering %T", prototype)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected MustRegister to panic")
+		}
+	}()
+	d.MustRegister("x", "not a struct")
+}
@@ -0,0 +1,97 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// positionPrefix starts a tag name holding a column position, e.g. `csv:"#3"`.
+// The tag name is still matched against the header, when there is one.
+const positionPrefix = "#"
+
+// parsePosition parses the 1-based column position of a `#N` tag name or of a
+// `pos=N` tag option.
+func parsePosition(s string) (int, error) {
+	pos, err := strconv.Atoi(s)
+	if err != nil || pos < 1 {
+		return 0, fmt.Errorf("invalid column position %q, positions start at 1", s)
+	}
+	return pos, nil
+}
+
+// isPositionTag tells whether the tag entry is a `#N` column position.
+func isPositionTag(entry string) bool {
+	return len(entry) > len(positionPrefix) && strings.HasPrefix(entry, positionPrefix) && entry[len(positionPrefix)] >= '0' && entry[len(positionPrefix)] <= '9'
+}
+
+// columnLayout maps the columns of records without header to struct fields.
+type columnLayout struct {
+	outInnerType reflect.Type
+	fields       []*fieldInfo // Field of each column, nil for the skipped columns
+	positional   bool         // Set when the fields have a column position: extra columns are skipped
+	failIfShort  bool         // Set when a record must have a column per field (see Config.FailIfShortRecords)
+}
+
+// newColumnLayout maps the columns to the fields of info. When some fields
+// have a column position (`csv:"#3"` or `csv:",pos=3"`), only these fields are
+// mapped, and the columns mapped to no field are skipped. Otherwise, the
+// columns are mapped to the fields in order.
+func newColumnLayout(cfg *Config, outInnerType reflect.Type, info *structInfo) (*columnLayout, error) {
+	layout := &columnLayout{outInnerType: outInnerType, failIfShort: cfg.FailIfShortRecords}
+	for i := range info.Fields {
+		field := &info.Fields[i]
+		if field.pos < 0 {
+			return nil, field.tagErr
+		} else if field.pos == 0 {
+			continue
+		}
+		layout.positional = true
+		for len(layout.fields) < field.pos {
+			layout.fields = append(layout.fields, nil)
+		}
+		if other := layout.fields[field.pos-1]; other != nil {
+			return nil, fmt.Errorf("column %d is mapped to both %s and %s", field.pos, getFieldPath(outInnerType, other.IndexChain), getFieldPath(outInnerType, field.IndexChain))
+		}
+		layout.fields[field.pos-1] = field
+	}
+	if !layout.positional {
+		for i := range info.Fields {
+			layout.fields = append(layout.fields, &info.Fields[i])
+		}
+	}
+	return layout, nil
+}
+
+// checkLength checks that the record found at the given line holds the
+// columns of the layout. With column positions, a record must reach the last
+// mapped column, and its extra columns are skipped. Otherwise, a record may
+// leave the last fields unset, unless failIfShort is set, but cannot have more
+// columns than fields.
+func (l *columnLayout) checkLength(record []string, line int) error {
+	if l.positional && len(record) < len(l.fields) {
+		field := l.fields[len(l.fields)-1]
+		return &csv.ParseError{
+			Line:   line,
+			Column: len(record) + 1,
+			Err:    fmt.Errorf("record has %d fields, but %s is mapped to column %d: %w", len(record), getFieldPath(l.outInnerType, field.IndexChain), len(l.fields), csv.ErrFieldCount),
+		}
+	}
+	if !l.positional && l.failIfShort && len(record) < len(l.fields) {
+		return &csv.ParseError{
+			Line:   line,
+			Column: len(record) + 1,
+			Err:    fmt.Errorf("record has %d fields, but %s has %d: %w", len(record), l.outInnerType, len(l.fields), csv.ErrFieldCount),
+		}
+	}
+	if !l.positional && len(record) > len(l.fields) {
+		return &csv.ParseError{
+			Line:   line,
+			Column: len(l.fields) + 1,
+			Err:    fmt.Errorf("record has %d fields, but %s has only %d: %w", len(record), l.outInnerType, len(l.fields), csv.ErrFieldCount),
+		}
+	}
+	return nil
+}
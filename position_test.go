@@ -0,0 +1,131 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type positionSample struct {
+	Amount float64 `csv:"amount,pos=4"`
+	ID     int     `csv:"#1"`
+	Name   string  `csv:"name,pos=2"`
+	Note   string
+}
+
+func TestPositionTags(t *testing.T) {
+	var samples []positionSample
+	in := "1,a,skipped,1.5\n2,b,,2.5,extra"
+	if err := UnmarshalWithoutHeaders(strings.NewReader(in), &samples, WithCSVReader(func(in io.Reader) CSVReader {
+		r := csv.NewReader(in)
+		r.FieldsPerRecord = -1
+		return r
+	})); err != nil {
+		t.Fatal(err)
+	}
+	expected := []positionSample{{ID: 1, Name: "a", Amount: 1.5}, {ID: 2, Name: "b", Amount: 2.5}}
+	if len(samples) != 2 || samples[0] != expected[0] || samples[1] != expected[1] {
+		t.Fatalf("expected %+v, got %+v", expected, samples)
+	}
+
+	// the positions do not prevent reading by header name
+	samples = nil
+	if err := UnmarshalString("name,amount\nc,3", &samples); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Name != "c" || samples[0].Amount != 3 {
+		t.Fatalf("unexpected sample %+v", samples[0])
+	}
+
+	// a #N tag name is still the name of a header column
+	samples = nil
+	if err := UnmarshalString("#1,name\n7,d", &samples); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].ID != 7 || samples[0].Name != "d" {
+		t.Fatalf("unexpected sample %+v", samples[0])
+	}
+}
+
+func TestPositionTagsShortRecord(t *testing.T) {
+	var samples []positionSample
+	err := UnmarshalWithoutHeaders(strings.NewReader("1,a,x,1.5\n2,b,x\n"), &samples, WithCSVReader(func(in io.Reader) CSVReader {
+		r := csv.NewReader(in)
+		r.FieldsPerRecord = -1
+		return r
+	}))
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, csv.ErrFieldCount) {
+		t.Fatalf("expected a field count error, got %v", err)
+	}
+	if parseErr.Line != 2 || !strings.Contains(err.Error(), "Amount is mapped to column 4") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestFailIfShortRecords(t *testing.T) {
+	raggedReader := WithCSVReader(func(in io.Reader) CSVReader {
+		r := csv.NewReader(in)
+		r.FieldsPerRecord = -1
+		return r
+	})
+	var samples []Sample
+	if err := UnmarshalWithoutHeaders(strings.NewReader("f,1,baz\ne,3\n"), &samples, raggedReader); err != nil {
+		t.Fatalf("expected a short record to be accepted by default, got %v", err)
+	}
+
+	var parseErr *csv.ParseError
+	err := UnmarshalWithoutHeaders(strings.NewReader("f,1,baz,0,0,x,x\ne,3\n"), &samples, raggedReader, WithFailIfShortRecords(true))
+	if !errors.As(err, &parseErr) || !errors.Is(err, csv.ErrFieldCount) || parseErr.Line != 2 || parseErr.Column != 3 {
+		t.Fatalf("expected a field count error on line 2, column 3, got %v", err)
+	}
+
+	err = UnmarshalString("foo,BAR,Baz\nf,1,baz\ne,3\n", &samples, raggedReader, WithFailIfShortRecords(true))
+	if !errors.As(err, &parseErr) || !errors.Is(err, csv.ErrFieldCount) || parseErr.Line != 3 || parseErr.Column != 3 {
+		t.Fatalf("expected a field count error on line 3, column 3, got %v", err)
+	}
+
+	um, err := NewUnmarshaller(csv.NewReader(strings.NewReader("foo,BAR,Baz\ne,3\n")), Sample{}, WithFailIfShortRecords(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	um.reader.FieldsPerRecord = -1
+	if _, err := um.Read(); !errors.As(err, &parseErr) || parseErr.Line != 2 {
+		t.Fatalf("expected a field count error on line 2, got %v", err)
+	}
+}
+
+func TestPositionTagsErrors(t *testing.T) {
+	type duplicate struct {
+		A string `csv:"#1"`
+		B string `csv:"b,pos=1"`
+	}
+	var d []duplicate
+	if err := UnmarshalWithoutHeaders(strings.NewReader("a"), &d); err == nil || !strings.Contains(err.Error(), "column 1 is mapped to both A and B") {
+		t.Fatalf("expected a duplicate position error, got %v", err)
+	}
+
+	type invalid struct {
+		A string `csv:"a,pos=0"`
+	}
+	var i []invalid
+	if err := UnmarshalWithoutHeaders(strings.NewReader("a"), &i); err == nil || !strings.Contains(err.Error(), "invalid column position") {
+		t.Fatalf("expected an invalid position error, got %v", err)
+	}
+}
+
+func TestWithoutHeadersErrorLine(t *testing.T) {
+	c := make(chan Sample)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- UnmarshalToChanWithoutHeaders(strings.NewReader("a,1\nb,x"), c)
+	}()
+	for range c {
+	}
+	var parseErr *csv.ParseError
+	if err := <-errs; !errors.As(err, &parseErr) || parseErr.Line != 2 {
+		t.Fatalf("expected an error on line 2, got %v", err)
+	}
+}
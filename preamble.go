@@ -0,0 +1,181 @@
+package gocsv
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// maxHeaderDetectionLines is the number of lines searched for the header row
+// when Config.DetectHeader is set.
+const maxHeaderDetectionLines = 100
+
+// hasPreamble tells whether the config skips lines before the header row.
+func (cfg *Config) hasPreamble() bool {
+	return cfg.SkipLines > 0 || cfg.SkipUntil != nil || cfg.DetectHeader
+}
+
+// preambleDecoder is a SimpleDecoder skipping the preamble of its input, i.e.
+// the lines before the header row, as told by the config. The preamble is
+// skipped on the first read, and the CSV reader then reads the rest.
+type preambleDecoder struct {
+	cfg      *Config
+	in       io.Reader
+	decoder  SimpleDecoder // Set once the preamble was skipped
+	skipped  int           // Number of lines skipped
+	keysType reflect.Type  // Struct type whose keys are searched for in the header row, if any
+	err      error
+}
+
+func newPreambleDecoder(cfg *Config, in io.Reader) *preambleDecoder {
+	return &preambleDecoder{cfg: cfg, in: in}
+}
+
+func (p *preambleDecoder) GetCSVRow() ([]string, error) {
+	if err := p.skipPreamble(); err != nil {
+		return nil, err
+	}
+	return p.decoder.GetCSVRow()
+}
+
+func (p *preambleDecoder) GetCSVRows() ([][]string, error) {
+	if err := p.skipPreamble(); err != nil {
+		return nil, err
+	}
+	return p.decoder.GetCSVRows()
+}
+
+// skipPreamble skips the preamble lines, unless already done.
+func (p *preambleDecoder) skipPreamble() error {
+	if p.decoder != nil || p.err != nil {
+		return p.err
+	}
+	in := bufio.NewReader(p.in)
+	var preamble []string
+	var replay []string // Lines read past the preamble, to read again
+	readLine := func() (string, error) {
+		line, err := in.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		return line, err
+	}
+
+	for i := 0; i < p.cfg.SkipLines; i++ {
+		line, err := readLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			p.err = err
+			return err
+		}
+		preamble = append(preamble, trimLineEnd(line))
+	}
+	if p.cfg.SkipUntil != nil {
+		for {
+			line, err := readLine()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				p.err = err
+				return err
+			}
+			if p.cfg.SkipUntil(trimLineEnd(line)) {
+				replay = append(replay, line)
+				break
+			}
+			preamble = append(preamble, trimLineEnd(line))
+		}
+	}
+	if p.cfg.DetectHeader && p.keysType != nil && len(replay) == 0 {
+		for len(replay) < maxHeaderDetectionLines {
+			line, err := readLine()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				p.err = err
+				return err
+			}
+			replay = append(replay, line)
+		}
+		header := findHeaderLine(p.cfg, p.keysType, replay)
+		for _, line := range replay[:header] {
+			preamble = append(preamble, trimLineEnd(line))
+		}
+		replay = replay[header:]
+	}
+
+	p.skipped = len(preamble)
+	if p.cfg.PreambleHandler != nil {
+		p.cfg.PreambleHandler(preamble)
+	}
+	p.decoder = csvDecoder{p.cfg.getCSVReader(io.MultiReader(strings.NewReader(strings.Join(replay, "")), in))}
+	return nil
+}
+
+// findHeaderLine returns the index of the line whose columns match the most
+// keys of the struct type t, the first one on a tie, or 0 if none matches.
+func findHeaderLine(cfg *Config, t reflect.Type, lines []string) int {
+	info := getStructInfo(cfg, t)
+	header, best := 0, 0
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		record, err := cfg.getCSVReader(strings.NewReader(line)).Read()
+		if err != nil {
+			continue
+		}
+		matches := 0
+		for _, column := range normalizeHeaders(cfg, record) {
+			for _, field := range info.Fields {
+				if field.matchesKey(column) {
+					matches++
+					break
+				}
+			}
+		}
+		if matches > best {
+			header, best = i, matches
+		}
+	}
+	return header
+}
+
+// trimLineEnd removes the line terminator, "\n" or "\r\n", of line.
+func trimLineEnd(line string) string {
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r")
+}
+
+// detectHeaderFor makes the decoder look for the header row of the struct type
+// elemType, a struct or a pointer to a struct, when Config.DetectHeader is set.
+func detectHeaderFor(decoder Decoder, elemType reflect.Type) {
+	if t, ok := decoder.(*trailerDecoder); ok {
+		decoder = t.decoder
+	}
+	if p, ok := decoder.(*preambleDecoder); ok && p.decoder == nil {
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() == reflect.Struct {
+			p.keysType = elemType
+		}
+	}
+}
+
+// preambleLines returns the number of lines skipped by the decoder before the
+// header row, or before the first record when the headers are given. The
+// preamble is skipped first if not done yet, an error being then returned by
+// the next read.
+func preambleLines(decoder Decoder) int {
+	if t, ok := decoder.(*trailerDecoder); ok {
+		decoder = t.decoder
+	}
+	if p, ok := decoder.(*preambleDecoder); ok {
+		p.skipPreamble()
+		return p.skipped
+	}
+	return 0
+}
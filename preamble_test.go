@@ -0,0 +1,108 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const preambleInput = "Sales report\r\nGenerated on 2024-01-02\n\nfoo,BAR\na,1\nb,x\n"
+
+func TestSkipLines(t *testing.T) {
+	var preamble []string
+	var samples []Sample
+	err := UnmarshalString(preambleInput, &samples, WithSkipLines(3), WithPreambleHandler(func(lines []string) {
+		preamble = lines
+	}))
+	expected := []string{"Sales report", "Generated on 2024-01-02", ""}
+	if !reflect.DeepEqual(preamble, expected) {
+		t.Fatalf("expected preamble %q, got %q", expected, preamble)
+	}
+	// the lines of the errors count the preamble
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 6 {
+		t.Fatalf("expected an error on line 6, got %v", err)
+	}
+	if samples[0].Foo != "a" || samples[0].Bar != 1 {
+		t.Fatalf("unexpected sample %+v", samples[0])
+	}
+}
+
+func TestSkipUntil(t *testing.T) {
+	var preamble []string
+	d, err := NewDecoder[Sample](strings.NewReader(preambleInput), WithSkipUntil(func(line string) bool {
+		return strings.HasPrefix(line, "foo,")
+	}), WithPreambleHandler(func(lines []string) {
+		preamble = lines
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preamble) != 3 {
+		t.Fatalf("expected 3 preamble lines, got %q", preamble)
+	}
+	s, err := d.Read()
+	if err != nil || s.Foo != "a" {
+		t.Fatalf("unexpected sample %+v, %v", s, err)
+	}
+
+	// without matching line, everything is skipped
+	var samples []Sample
+	if err := UnmarshalString(preambleInput, &samples, WithSkipUntil(func(string) bool { return false })); err != ErrEmptyCSVFile {
+		t.Fatalf("expected ErrEmptyCSVFile, got %v", err)
+	}
+}
+
+func TestDetectHeader(t *testing.T) {
+	in := "Report,Sample\nfoo: totals,BAR,x\nfoo,BAR,Baz\na,1,b\n"
+	var preamble []string
+	var samples []Sample
+	err := UnmarshalString(in, &samples, WithDetectHeader(), WithPreambleHandler(func(lines []string) {
+		preamble = lines
+	}), WithCSVReader(func(in io.Reader) CSVReader {
+		r := csv.NewReader(in)
+		r.FieldsPerRecord = -1
+		return r
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preamble) != 2 {
+		t.Fatalf("expected 2 preamble lines, got %q", preamble)
+	}
+	if len(samples) != 1 || samples[0].Foo != "a" || samples[0].Bar != 1 || samples[0].Baz != "b" {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+
+	// a file starting with its header is read as is
+	c := make(chan Sample, 1)
+	if err := UnmarshalToChan(strings.NewReader("foo,BAR\na,1"), c, WithDetectHeader()); err != nil {
+		t.Fatal(err)
+	}
+	if s := <-c; s.Foo != "a" {
+		t.Fatalf("unexpected sample %+v", s)
+	}
+}
+
+func TestSkipLinesWithHeaders(t *testing.T) {
+	in := "Sales report\nGenerated on 2024-01-02\na,1\nb,x\n"
+	var samples []Sample
+	err := UnmarshalWithHeaders(strings.NewReader(in), []string{"foo", "BAR"}, &samples, WithSkipLines(2), WithCollectErrors(0))
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs.Errors) != 1 {
+		t.Fatalf("expected 1 collected error, got %v", err)
+	}
+	if line := decodeErrs.Errors[0].Line; line != 4 {
+		t.Fatalf("expected the error on line 4, got %d", line)
+	}
+
+	c := make(chan Sample, 2)
+	err = UnmarshalToChanWithHeaders(strings.NewReader(in), []string{"foo", "BAR"}, c, WithSkipLines(2))
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 4 {
+		t.Fatalf("expected an error on line 4, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package gocsv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// QuotePolicy tells which fields are quoted when writing CSV.
+type QuotePolicy int
+
+const (
+	// QuoteMinimal only quotes the fields holding a separator, a quote, an
+	// escape character, a line break or leading space.
+	QuoteMinimal QuotePolicy = iota
+	// QuoteAll quotes every field.
+	QuoteAll
+	// QuoteNonNumeric quotes every field but those of integer and float
+	// types. The header row is quoted.
+	QuoteNonNumeric
+	// QuoteNone never quotes the fields, the separators, quotes, escape
+	// characters and line breaks being preceded by the escape character of the
+	// dialect (see Dialect.Escape), which must then be set.
+	QuoteNone
+)
+
+func (p QuotePolicy) String() string {
+	switch p {
+	case QuoteMinimal:
+		return "minimal"
+	case QuoteAll:
+		return "all"
+	case QuoteNonNumeric:
+		return "nonnumeric"
+	case QuoteNone:
+		return "none"
+	}
+	return fmt.Sprintf("QuotePolicy(%d)", int(p))
+}
+
+var (
+	// errNoEscape is returned when a field written with QuoteNone holds a
+	// character to escape, while the dialect has no escape character.
+	errNoEscape = errors.New("QuoteNone needs an escape character for this field")
+
+	// errQuotingUnsupported is returned when a quoting policy other than
+	// QuoteMinimal is used with a CSV writer unable to apply it.
+	errQuotingUnsupported = errors.New("the CSV writer does not support quoting policies")
+)
+
+// quotingWriter is implemented by the CSV writers applying quoting policies.
+type quotingWriter interface {
+	// writeQuoted writes record, each field being quoted as told by quoting,
+	// which only holds QuoteMinimal, QuoteAll and QuoteNone.
+	writeQuoted(record []string, quoting []QuotePolicy) error
+}
+
+// writeRecord writes record, each field being quoted as told by quoting, or
+// as the writer chooses when quoting is nil.
+func writeRecord(writer CSVWriter, record []string, quoting []QuotePolicy) error {
+	if quoting == nil {
+		return writer.Write(record)
+	}
+	if writer, ok := writer.(quotingWriter); ok {
+		return writer.writeQuoted(record, quoting)
+	}
+	return fmt.Errorf("%w: %T", errQuotingUnsupported, writer)
+}
+
+// parseQuotePolicy parses the value of the quote tag option.
+func parseQuotePolicy(s string) (QuotePolicy, error) {
+	for _, p := range []QuotePolicy{QuoteMinimal, QuoteAll, QuoteNonNumeric, QuoteNone} {
+		if s == p.String() {
+			return p, nil
+		}
+	}
+	return QuoteMinimal, fmt.Errorf("invalid quote policy %q", s)
+}
+
+var stringType = reflect.TypeOf("")
+
+// isNumericType tells whether the values of type t are written as numbers.
+func isNumericType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return false
+	}
+	// the marshal methods take precedence over the kind (cf. getFieldAsString)
+	for _, marshaler := range []reflect.Type{marshalerType, textMarshalerType, valuerType} {
+		if t.Implements(marshaler) || reflect.PtrTo(t).Implements(marshaler) {
+			return false
+		}
+	}
+	return true
+}
+
+// cellQuoting resolves the quoting of a cell holding a value of type t:
+// QuoteNonNumeric becomes QuoteMinimal for numbers, and QuoteAll otherwise.
+func cellQuoting(policy QuotePolicy, t reflect.Type) QuotePolicy {
+	if policy != QuoteNonNumeric {
+		return policy
+	} else if isNumericType(t) {
+		return QuoteMinimal
+	}
+	return QuoteAll
+}
+
+// columnType returns the type of the values written in the column of the
+// field, through the pointers, and the slices and maps of the dynamic columns.
+func columnType(t reflect.Type, fieldInfo *fieldInfo) reflect.Type {
+	for _, i := range fieldInfo.IndexChain {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			t = t.Elem()
+		case reflect.Struct:
+			t = t.Field(i).Type
+		default:
+			return t
+		}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if fieldInfo.mapKey != "" && t.Kind() == reflect.Map {
+		t = t.Elem()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	if fieldInfo.split != "" && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		return stringType // the elements are joined in a single cell
+	}
+	return t
+}
+
+// quotePolicy returns the policy of the column of the field: its quote tag
+// option, or Config.Quoting.
+func (cfg *Config) quotePolicy(fieldInfo *fieldInfo) QuotePolicy {
+	if fieldInfo.quoteTag {
+		return fieldInfo.quoting
+	}
+	return cfg.Quoting
+}
+
+// recordQuoting returns the quoting of the header and of the records written
+// for the struct info of type t, followed by the columns of remainKeys. Both
+// are nil when every cell is quoted minimally.
+func (cfg *Config) recordQuoting(t reflect.Type, info *structInfo, remainKeys []string) (header, record []QuotePolicy) {
+	header = make([]QuotePolicy, 0, len(info.Fields)+len(remainKeys))
+	record = make([]QuotePolicy, 0, len(info.Fields)+len(remainKeys))
+	minimal := true
+	add := func(policy QuotePolicy, t reflect.Type) {
+		header = append(header, cellQuoting(policy, stringType))
+		record = append(record, cellQuoting(policy, t))
+		minimal = minimal && policy == QuoteMinimal
+	}
+	for i := range info.Fields {
+		add(cfg.quotePolicy(&info.Fields[i]), columnType(t, &info.Fields[i]))
+	}
+	for range remainKeys {
+		add(cfg.Quoting, stringType)
+	}
+	if minimal {
+		return nil, nil
+	}
+	return header, record
+}
@@ -0,0 +1,170 @@
+package gocsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type quotingCode int
+
+func (c quotingCode) MarshalCSV() (string, error) {
+	return "C" + strings.Repeat("0", int(c)), nil
+}
+
+type quotingSample struct {
+	ID     int         `csv:"id"`
+	Name   string      `csv:"name"`
+	Price  *float64    `csv:"price"`
+	Active bool        `csv:"active"`
+	Code   quotingCode `csv:"code"`
+}
+
+func newQuotingSamples() []quotingSample {
+	price := 1.5
+	return []quotingSample{{1, "Bob", &price, true, 2}, {2, "Smith, John", nil, false, 0}}
+}
+
+func TestQuoting(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		out  string
+	}{
+		{"minimal", []Option{WithQuoting(QuoteMinimal)},
+			"id,name,price,active,code\n1,Bob,1.5,true,C00\n2,\"Smith, John\",,false,C\n"},
+		{"all", []Option{WithQuoting(QuoteAll)},
+			"\"id\",\"name\",\"price\",\"active\",\"code\"\n\"1\",\"Bob\",\"1.5\",\"true\",\"C00\"\n\"2\",\"Smith, John\",\"\",\"false\",\"C\"\n"},
+		{"non numeric", []Option{WithQuoting(QuoteNonNumeric)},
+			"\"id\",\"name\",\"price\",\"active\",\"code\"\n1,\"Bob\",1.5,\"true\",\"C00\"\n2,\"Smith, John\",,\"false\",\"C\"\n"},
+		{"none", []Option{WithQuoting(QuoteNone), WithDialect(Dialect{Escape: '\\'})},
+			"id,name,price,active,code\n1,Bob,1.5,true,C00\n2,Smith\\, John,,false,C\n"},
+		{"all with dialect", []Option{WithQuoting(QuoteAll), WithDialect(Dialect{Delimiter: ';', Quote: '\'', LineTerminator: "\r\n"})},
+			"'id';'name';'price';'active';'code'\r\n'1';'Bob';'1.5';'true';'C00'\r\n'2';'Smith, John';'';'false';'C'\r\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Marshal(newQuotingSamples(), &buf, test.opts...); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != test.out {
+				t.Fatalf("expected %q, got %q", test.out, buf.String())
+			}
+		})
+	}
+}
+
+func TestQuotingReadBack(t *testing.T) {
+	type sample struct {
+		Name string  `csv:"name"`
+		Note string  `csv:"note"`
+		Rate float64 `csv:"rate"`
+	}
+	samples := []sample{{"a,b", "it's \"quoted\"\nhere", 1}, {`c\d`, "", 2.5}}
+	dialect := Dialect{Escape: '\\'}
+	for _, policy := range []QuotePolicy{QuoteMinimal, QuoteAll, QuoteNonNumeric, QuoteNone} {
+		var buf bytes.Buffer
+		if err := Marshal(samples, &buf, WithQuoting(policy), WithDialect(dialect)); err != nil {
+			t.Fatal(err)
+		}
+		var decoded []sample
+		if err := Unmarshal(&buf, &decoded, WithDialect(dialect)); err != nil {
+			t.Fatalf("%v: %v", policy, err)
+		}
+		if len(decoded) != 2 || decoded[0] != samples[0] || decoded[1] != samples[1] {
+			t.Fatalf("%v: unexpected samples %+v", policy, decoded)
+		}
+	}
+}
+
+func TestQuotingTag(t *testing.T) {
+	type sample struct {
+		Quote string `csv:"quote"`
+		ID    int    `csv:"id,quote"`
+		Name  string `csv:"name,quote=none"`
+		Rate  int    `csv:"rate,quote=nonnumeric"`
+	}
+	var buf bytes.Buffer
+	if err := Marshal([]sample{{"q", 1, "Bob", 2}}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "quote,\"id\",name,\"rate\"\nq,\"1\",Bob,2\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+
+	// the tag overrides the config
+	buf.Reset()
+	if err := Marshal([]sample{{"q", 1, "Bob", 2}}, &buf, WithQuoting(QuoteAll)); err != nil {
+		t.Fatal(err)
+	}
+	expected = "\"quote\",\"id\",name,\"rate\"\n\"q\",\"1\",Bob,2\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+
+	type invalid struct {
+		Name string `csv:"name,quote=always"`
+	}
+	if err := Marshal([]invalid{{"Bob"}}, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "invalid quote policy") {
+		t.Fatalf("expected an invalid quote policy error, got %v", err)
+	}
+}
+
+func TestQuotingErrors(t *testing.T) {
+	err := Marshal(newQuotingSamples(), &bytes.Buffer{}, WithQuoting(QuoteNone))
+	if !errors.Is(err, errNoEscape) {
+		t.Fatalf("expected errNoEscape, got %v", err)
+	}
+
+	writer := NewSafeCSVWriter(csv.NewWriter(&bytes.Buffer{}))
+	err = MarshalCSV(newQuotingSamples(), writer, WithQuoting(QuoteAll))
+	if !errors.Is(err, errQuotingUnsupported) {
+		t.Fatalf("expected errQuotingUnsupported, got %v", err)
+	}
+}
+
+func TestQuotingEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := NewEncoder[quotingSample](&buf, WithQuoting(QuoteNonNumeric))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sample := range newQuotingSamples() {
+		if err := enc.Encode(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "\"id\",\"name\",\"price\",\"active\",\"code\"\n1,\"Bob\",1.5,\"true\",\"C00\"\n2,\"Smith, John\",,\"false\",\"C\"\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestQuotingPolymorphic(t *testing.T) {
+	type payment struct {
+		ID     string  `csv:"id"`
+		Amount float64 `csv:"amount"`
+	}
+	type refund struct {
+		ID     string `csv:"id"`
+		Reason string `csv:"reason"`
+	}
+	d := NewDiscriminator("type").MustRegister("payment", payment{}).MustRegister("refund", refund{})
+	events := []interface{}{payment{"p1", 10}, refund{"r1", "damaged"}}
+
+	var buf bytes.Buffer
+	if err := MarshalPolymorphic(events, &buf, d, WithQuoting(QuoteNonNumeric)); err != nil {
+		t.Fatal(err)
+	}
+	expected := "\"type\",\"id\",\"amount\",\"reason\"\n\"payment\",\"p1\",10,\"\"\n\"refund\",\"r1\",\"\",\"damaged\"\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -6,13 +6,16 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // --------------------------------------------------------------------------
 // Reflection helpers
 
 type structInfo struct {
-	Fields []fieldInfo
+	Fields  []fieldInfo
+	remain  *fieldInfo         // map field collecting the unmatched columns, if any
+	dynamic []dynamicFieldInfo // slice and map fields whose columns are found at run time
 }
 
 // fieldInfo is a struct field that should be mapped to a CSV column, or vice-versa
@@ -25,6 +28,20 @@ type fieldInfo struct {
 	defaultValue string
 	partial      bool
 	inline       bool
+	rules        []validationRule
+	timeLayouts  []string       // layouts of a time.Time field, the first one being used to encode
+	location     *time.Location // time zone of a time.Time field
+	nullTokens   []string       // cell values standing for a null value
+	split        string         // separator of the elements of a slice field held in a single cell
+	remain       bool           // map field collecting the unmatched columns
+	dynamic      bool           // slice or map field whose element columns are found at run time
+	children     []fieldInfo    // fields of the elements of a dynamic slice of structs
+	prefix       bool           // map field whose entries are the columns starting with its key
+	mapKey       string         // key of the map entry held by the column of a prefix map field
+	pos          int            // 1-based column position in records without header, 0 if none, -1 if invalid
+	quoting      QuotePolicy    // quoting of the column when written, if quoteTag
+	quoteTag     bool           // quoting set by the quote tag option
+	tagErr       error          // invalid tag option, reported when the field is converted
 }
 
 func (f fieldInfo) getFirstKey() string {
@@ -61,20 +78,71 @@ var structInfoCache sync.Map
 var structMap = make(map[reflect.Type]*structInfo)
 var structMapMutex sync.RWMutex
 
-func getStructInfo(rType reflect.Type) *structInfo {
-	stInfo, ok := structInfoCache.Load(rType)
-	if ok {
+// structInfoKey identifies a struct info in the cache: the same type may be
+// read with different tag settings. The cached keys are not normalized, so
+// that the header normalizer does not need to be part of the key.
+type structInfoKey struct {
+	rType          reflect.Type
+	tagName        string
+	tagSeparator   string
+	fieldsCombiner string
+}
+
+func getStructInfo(cfg *Config, rType reflect.Type) *structInfo {
+	key := structInfoKey{rType, cfg.TagName, cfg.TagSeparator, cfg.FieldsCombiner}
+	stInfo, ok := structInfoCache.Load(key)
+	if !ok {
+		fieldsList := getFieldInfos(cfg, rType, []int{}, []string{})
+		stInfo, _ = structInfoCache.LoadOrStore(key, newStructInfo(fieldsList))
+	}
+	if cfg.HeaderNormalizer == nil && len(cfg.NullTokens) == 0 {
 		return stInfo.(*structInfo)
 	}
+	return configureStructInfo(cfg, stInfo.(*structInfo))
+}
 
-	fieldsList := getFieldInfos(rType, []int{}, []string{})
-	stInfo = &structInfo{fieldsList}
-	structInfoCache.Store(rType, stInfo)
+// newStructInfo builds the struct info of the given fields, setting aside the
+// first one with the remain tag option and the dynamic slices and maps.
+func newStructInfo(fieldsList []fieldInfo) *structInfo {
+	info := &structInfo{Fields: make([]fieldInfo, 0, len(fieldsList))}
+	for i, field := range fieldsList {
+		if field.dynamic {
+			info.dynamic = append(info.dynamic, dynamicFieldInfo{fieldInfo: field, pos: len(info.Fields)})
+		} else if !field.remain {
+			info.Fields = append(info.Fields, field)
+		} else if info.remain == nil {
+			info.remain = &fieldsList[i]
+		}
+	}
+	return info
+}
 
-	return stInfo.(*structInfo)
+// configureStructInfo returns a copy of stInfo adapted to the config (see configureFieldInfo).
+// The dynamic slices and maps are configured when they are expanded.
+func configureStructInfo(cfg *Config, stInfo *structInfo) *structInfo {
+	fieldsList := make([]fieldInfo, len(stInfo.Fields))
+	for i, field := range stInfo.Fields {
+		fieldsList[i] = configureFieldInfo(cfg, field)
+	}
+	return &structInfo{Fields: fieldsList, remain: stInfo.remain, dynamic: stInfo.dynamic}
 }
 
-func getFieldInfos(rType reflect.Type, parentIndexChain []int, parentKeys []string) []fieldInfo {
+// configureFieldInfo returns field adapted to the config: its keys went through
+// the header normalizer, and it uses the null tokens of the config when it has
+// no null tag option.
+func configureFieldInfo(cfg *Config, field fieldInfo) fieldInfo {
+	keys := make([]string, len(field.keys))
+	for j, key := range field.keys {
+		keys[j] = cfg.normalize(key)
+	}
+	field.keys = keys
+	if len(field.nullTokens) == 0 {
+		field.nullTokens = cfg.NullTokens
+	}
+	return field
+}
+
+func getFieldInfos(cfg *Config, rType reflect.Type, parentIndexChain []int, parentKeys []string) []fieldInfo {
 	fieldsCount := rType.NumField()
 	fieldsList := make([]fieldInfo, 0, fieldsCount)
 	for i := 0; i < fieldsCount; i++ {
@@ -99,7 +167,7 @@ func getFieldInfos(rType reflect.Type, parentIndexChain []int, parentKeys []stri
 		var currFieldInfo *fieldInfo
 		if !field.Anonymous {
 			filteredTags := []string{}
-			currFieldInfo, filteredTags = filterTags(TagName, indexChain, field)
+			currFieldInfo, filteredTags = filterTags(cfg, indexChain, field)
 
 			if len(filteredTags) == 1 && filteredTags[0] == "-" {
 				// ignore nested structs with - tag
@@ -111,7 +179,7 @@ func getFieldInfos(rType reflect.Type, parentIndexChain []int, parentKeys []stri
 			} else if len(filteredTags) > 0 && filteredTags[0] != "" {
 				currFieldInfo.keys = filteredTags
 			} else {
-				currFieldInfo.keys = []string{normalizeName(field.Name)}
+				currFieldInfo.keys = []string{field.Name}
 			}
 
 			if len(parentKeys) > 0 && currFieldInfo != nil && !currFieldInfo.inline {
@@ -120,7 +188,7 @@ func getFieldInfos(rType reflect.Type, parentIndexChain []int, parentKeys []stri
 				keys := make([]string, 0, len(parentKeys)*len(currFieldInfo.keys))
 				for _, pkey := range parentKeys {
 					for _, ckey := range currFieldInfo.keys {
-						keys = append(keys, normalizeName(fmt.Sprintf("%s%s%s", pkey, FieldsCombiner, ckey)))
+						keys = append(keys, fmt.Sprintf("%s%s%s", pkey, cfg.FieldsCombiner, ckey))
 					}
 					currFieldInfo.keys = keys
 				}
@@ -134,7 +202,7 @@ func getFieldInfos(rType reflect.Type, parentIndexChain []int, parentKeys []stri
 			if currFieldInfo != nil {
 				keys = currFieldInfo.keys
 			}
-			fieldsList = append(fieldsList, getFieldInfos(fieldType, indexChain, keys)...)
+			fieldsList = append(fieldsList, getFieldInfos(cfg, fieldType, indexChain, keys)...)
 			continue
 		}
 
@@ -143,16 +211,32 @@ func getFieldInfos(rType reflect.Type, parentIndexChain []int, parentKeys []stri
 			continue
 		}
 
-		if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Array {
+		if currFieldInfo.prefix && currFieldInfo.tagErr == nil {
+			// the columns of the map entries are found in the header row, or in the values to write
+			currFieldInfo.dynamic = true
+			fieldsList = append(fieldsList, *currFieldInfo)
+		} else if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Array {
 			var arrayLength = -1
 			// if the field is a slice or an array, see if it has a `csv[n]` tag
-			if arrayTag, ok := field.Tag.Lookup(TagName + "[]"); ok {
-				arrayLength, _ = strconv.Atoi(arrayTag)
+			if arrayTag, ok := field.Tag.Lookup(cfg.TagName + "[]"); ok {
+				if arrayTag == dynamicArrayLength && field.Type.Kind() == reflect.Slice {
+					// the indexes are found in the header row, or in the values to write
+					currFieldInfo.dynamic = true
+					if field.Type.Elem().Kind() == reflect.Struct {
+						currFieldInfo.children = getFieldInfos(cfg, field.Type.Elem(), []int{}, []string{})
+					}
+					fieldsList = append(fieldsList, *currFieldInfo)
+					continue
+				} else if arrayTag == dynamicArrayLength {
+					arrayLength = field.Type.Len()
+				} else {
+					arrayLength, _ = strconv.Atoi(arrayTag)
+				}
 			}
 
 			// slices or arrays of Struct get special handling
 			if field.Type.Elem().Kind() == reflect.Struct {
-				fieldInfos := getFieldInfos(field.Type.Elem(), []int{}, []string{})
+				fieldInfos := getFieldInfos(cfg, field.Type.Elem(), []int{}, []string{})
 
 				// if no special csv[] tag was supplied, just include the field directly
 				if arrayLength == -1 {
@@ -160,53 +244,15 @@ func getFieldInfos(rType reflect.Type, parentIndexChain []int, parentKeys []stri
 				} else {
 					// When the field is a slice/array of structs, create a fieldInfo for each index and each field
 					for idx := 0; idx < arrayLength; idx++ {
-						// copy index chain and append array index
-						var cpy2 = make([]int, len(indexChain))
-						copy(cpy2, indexChain)
-						arrayIndexChain := append(cpy2, idx)
 						for _, childFieldInfo := range fieldInfos {
-							// copy array index chain and append array index
-							var cpy3 = make([]int, len(arrayIndexChain))
-							copy(cpy3, arrayIndexChain)
-
-							arrayFieldInfo := fieldInfo{
-								IndexChain:   append(cpy3, childFieldInfo.IndexChain...),
-								omitEmpty:    childFieldInfo.omitEmpty,
-								defaultValue: childFieldInfo.defaultValue,
-								partial:      childFieldInfo.partial,
-							}
-
-							// create cartesian product of keys
-							// eg: array field keys x struct field keys
-							for _, akey := range currFieldInfo.keys {
-								for _, fkey := range childFieldInfo.keys {
-									arrayFieldInfo.keys = append(arrayFieldInfo.keys, normalizeName(fmt.Sprintf("%s[%d].%s", akey, idx, fkey)))
-								}
-							}
-
-							fieldsList = append(fieldsList, arrayFieldInfo)
+							fieldsList = append(fieldsList, getArrayElemFieldInfo(currFieldInfo, idx, &childFieldInfo))
 						}
 					}
 				}
 			} else if arrayLength > 0 {
 				// When the field is a slice/array of primitives, create a fieldInfo for each index
 				for idx := 0; idx < arrayLength; idx++ {
-					// copy index chain and append array index
-					var cpy2 = make([]int, len(indexChain))
-					copy(cpy2, indexChain)
-
-					arrayFieldInfo := fieldInfo{
-						IndexChain:   append(cpy2, idx),
-						omitEmpty:    currFieldInfo.omitEmpty,
-						defaultValue: currFieldInfo.defaultValue,
-						partial:      currFieldInfo.partial,
-					}
-
-					for _, akey := range currFieldInfo.keys {
-						arrayFieldInfo.keys = append(arrayFieldInfo.keys, normalizeName(fmt.Sprintf("%s[%d]", akey, idx)))
-					}
-
-					fieldsList = append(fieldsList, arrayFieldInfo)
+					fieldsList = append(fieldsList, getArrayElemFieldInfo(currFieldInfo, idx, nil))
 				}
 			} else {
 				fieldsList = append(fieldsList, *currFieldInfo)
@@ -218,14 +264,48 @@ func getFieldInfos(rType reflect.Type, parentIndexChain []int, parentKeys []stri
 	return fieldsList
 }
 
-func filterTags(tagName string, indexChain []int, field reflect.StructField) (*fieldInfo, []string) {
+// getArrayElemFieldInfo returns the fieldInfo of the element idx of the slice
+// or array field arrayFieldInfo, or of the field childFieldInfo of that element
+// when it is a struct. Its keys are "key[idx]" or "key[idx].childKey".
+func getArrayElemFieldInfo(arrayFieldInfo *fieldInfo, idx int, childFieldInfo *fieldInfo) fieldInfo {
+	// copy index chain and append array index
+	indexChain := make([]int, len(arrayFieldInfo.IndexChain), len(arrayFieldInfo.IndexChain)+1)
+	copy(indexChain, arrayFieldInfo.IndexChain)
+	indexChain = append(indexChain, idx)
+
+	// keep the tag options of the field
+	elemFieldInfo := *arrayFieldInfo
+	if childFieldInfo != nil {
+		elemFieldInfo = *childFieldInfo
+		indexChain = append(indexChain, childFieldInfo.IndexChain...)
+	}
+	elemFieldInfo.IndexChain = indexChain
+	elemFieldInfo.dynamic = false
+	elemFieldInfo.children = nil
+	elemFieldInfo.keys = nil
+
+	// create cartesian product of keys
+	// eg: array field keys x struct field keys
+	for _, akey := range arrayFieldInfo.keys {
+		if childFieldInfo == nil {
+			elemFieldInfo.keys = append(elemFieldInfo.keys, fmt.Sprintf("%s[%d]", akey, idx))
+			continue
+		}
+		for _, fkey := range childFieldInfo.keys {
+			elemFieldInfo.keys = append(elemFieldInfo.keys, fmt.Sprintf("%s[%d].%s", akey, idx, fkey))
+		}
+	}
+	return elemFieldInfo
+}
+
+func filterTags(cfg *Config, indexChain []int, field reflect.StructField) (*fieldInfo, []string) {
 	currFieldInfo := fieldInfo{IndexChain: indexChain}
 
-	fieldTag := field.Tag.Get(tagName)
-	fieldTags := strings.Split(fieldTag, TagSeparator)
+	fieldTag := field.Tag.Get(cfg.TagName)
+	fieldTags := strings.Split(fieldTag, cfg.TagSeparator)
 
 	filteredTags := []string{}
-	for _, fieldTagEntry := range fieldTags {
+	for i, fieldTagEntry := range fieldTags {
 		trimmedFieldTagEntry := strings.TrimSpace(fieldTagEntry) // handles cases like `csv:"foo, omitempty, default=test"`
 		if trimmedFieldTagEntry == "omitempty" {
 			currFieldInfo.omitEmpty = true
@@ -236,8 +316,62 @@ func filterTags(tagName string, indexChain []int, field reflect.StructField) (*f
 			currFieldInfo.partial = true
 		} else if strings.HasPrefix(trimmedFieldTagEntry, "default=") {
 			currFieldInfo.defaultValue = strings.TrimPrefix(trimmedFieldTagEntry, "default=")
+		} else if trimmedFieldTagEntry == "remain" && i > 0 {
+			// a bare "remain" first entry is the column name, as in `csv:"remain"`
+			currFieldInfo.remain = true
+			if !isStringMap(field.Type) {
+				currFieldInfo.tagErr = fmt.Errorf("field %s has the remain tag option but is not a map[string]string", field.Name)
+			}
+		} else if trimmedFieldTagEntry == "prefix" && i > 0 {
+			// a bare "prefix" first entry is the column name, as in `csv:"prefix"`
+			currFieldInfo.prefix = true
+			if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+				currFieldInfo.tagErr = fmt.Errorf("field %s has the prefix tag option but is not a map with string keys", field.Name)
+			}
+		} else if strings.HasPrefix(trimmedFieldTagEntry, "layout=") {
+			currFieldInfo.timeLayouts = append(currFieldInfo.timeLayouts, strings.TrimPrefix(trimmedFieldTagEntry, "layout="))
+		} else if strings.HasPrefix(trimmedFieldTagEntry, "tz=") {
+			location, err := time.LoadLocation(strings.TrimPrefix(trimmedFieldTagEntry, "tz="))
+			if err != nil {
+				currFieldInfo.tagErr = fmt.Errorf("invalid time zone in tag %q: %w", trimmedFieldTagEntry, err)
+			}
+			currFieldInfo.location = location
+		} else if strings.HasPrefix(trimmedFieldTagEntry, "split=") {
+			currFieldInfo.split = strings.TrimPrefix(trimmedFieldTagEntry, "split=")
+		} else if strings.HasPrefix(trimmedFieldTagEntry, "null=") {
+			currFieldInfo.nullTokens = append(currFieldInfo.nullTokens, strings.TrimPrefix(trimmedFieldTagEntry, "null="))
+		} else if strings.HasPrefix(trimmedFieldTagEntry, "pos=") || isPositionTag(trimmedFieldTagEntry) {
+			pos, err := parsePosition(strings.TrimLeft(strings.TrimPrefix(trimmedFieldTagEntry, "pos="), positionPrefix))
+			if i == 0 {
+				// the first entry is still the column name, as in `csv:"#1"`: the
+				// position only applies to records without header
+				filteredTags = append(filteredTags, trimmedFieldTagEntry)
+				if err == nil {
+					currFieldInfo.pos = pos
+				}
+				continue
+			}
+			if err != nil {
+				pos = -1
+				currFieldInfo.tagErr = fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			currFieldInfo.pos = pos
+		} else if (trimmedFieldTagEntry == "quote" && i > 0) || strings.HasPrefix(trimmedFieldTagEntry, "quote=") {
+			// a bare "quote" first entry is the column name, as in `csv:"quote"`
+			currFieldInfo.quoting, currFieldInfo.quoteTag = QuoteAll, true
+			if value := strings.TrimPrefix(trimmedFieldTagEntry, "quote="); value != trimmedFieldTagEntry {
+				quoting, err := parseQuotePolicy(value)
+				if err != nil {
+					currFieldInfo.tagErr = fmt.Errorf("field %s: %w", field.Name, err)
+				}
+				currFieldInfo.quoting = quoting
+			}
+		} else if i > 0 && isValidationRule(trimmedFieldTagEntry) {
+			// the first entry is the column name, even when named like a rule,
+			// as in `csv:"required"`
+			currFieldInfo.rules = append(currFieldInfo.rules, parseValidationRule(trimmedFieldTagEntry))
 		} else {
-			filteredTags = append(filteredTags, normalizeName(trimmedFieldTagEntry))
+			filteredTags = append(filteredTags, trimmedFieldTagEntry)
 		}
 	}
 
@@ -0,0 +1,101 @@
+package gocsv
+
+import (
+	"reflect"
+	"sort"
+)
+
+// --------------------------------------------------------------------------
+// Catch-all field
+//
+// A map[string]string field tagged with the remain option, e.g.
+// `csv:",remain"`, is filled with the columns that match no other field when
+// decoding, header -> value. When encoding, its keys are written as extra
+// columns after the other fields.
+
+func isStringMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String
+}
+
+// setRemainField stores value under header in the remain field of outInner,
+// creating the map and the nil pointers leading to it when needed.
+func setRemainField(outInner reflect.Value, remain *fieldInfo, header, value string) error {
+	if remain.tagErr != nil {
+		return remain.tagErr
+	}
+	field := outInner
+	for _, i := range remain.IndexChain {
+		for field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+		field = field.Field(i)
+	}
+	if field.IsNil() {
+		field.Set(reflect.MakeMap(field.Type()))
+	}
+	field.SetMapIndex(reflect.ValueOf(header).Convert(field.Type().Key()), reflect.ValueOf(value).Convert(field.Type().Elem()))
+	return nil
+}
+
+// getRemainField returns the remain field of inInner, or an invalid value
+// when it cannot be reached.
+func getRemainField(inInner reflect.Value, remain *fieldInfo) reflect.Value {
+	return getInnerFieldValue(inInner, remain.IndexChain)
+}
+
+// addMapKeys adds to keys the keys of the map field, if valid.
+func addMapKeys(keys map[string]struct{}, field reflect.Value) {
+	if !field.IsValid() || field.Kind() != reflect.Map {
+		return
+	}
+	iter := field.MapRange()
+	for iter.Next() {
+		keys[iter.Key().String()] = struct{}{}
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// getRemainKeys returns the sorted union of the keys of the remain fields of
+// the count values returned by value, which are written as extra columns.
+func getRemainKeys(info *structInfo, count int, value func(i int) reflect.Value) []string {
+	if info.remain == nil {
+		return nil
+	}
+	set := make(map[string]struct{})
+	for i := 0; i < count; i++ {
+		addMapKeys(set, getRemainField(value(i), info.remain))
+	}
+	return sortedKeys(set)
+}
+
+// fillRemainRecord stores in record the values of the remain field of inInner
+// for the given keys, an empty string for the missing ones.
+func fillRemainRecord(record []string, inInner reflect.Value, info *structInfo, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if info.remain.tagErr != nil {
+		return info.remain.tagErr
+	}
+	field := getRemainField(inInner, info.remain)
+	for i, key := range keys {
+		record[i] = ""
+		if field.IsValid() && !field.IsNil() {
+			if v := field.MapIndex(reflect.ValueOf(key).Convert(field.Type().Key())); v.IsValid() {
+				record[i] = v.String()
+			}
+		}
+	}
+	return nil
+}
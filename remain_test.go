@@ -0,0 +1,167 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type remainSample struct {
+	ID    int               `csv:"id"`
+	Name  string            `csv:"name"`
+	Extra map[string]string `csv:",remain"`
+}
+
+func TestRemainDecode(t *testing.T) {
+	in := "id,color,name,size\n1,red,a,L\n2,,b,M"
+	var samples []remainSample
+	if err := UnmarshalString(in, &samples); err != nil {
+		t.Fatal(err)
+	}
+	expected := []remainSample{
+		{ID: 1, Name: "a", Extra: map[string]string{"color": "red", "size": "L"}},
+		{ID: 2, Name: "b", Extra: map[string]string{"color": "", "size": "M"}},
+	}
+	if !reflect.DeepEqual(samples, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, samples)
+	}
+
+	c := make(chan *remainSample)
+	go func() {
+		if err := UnmarshalToChan(strings.NewReader(in), c); err != nil {
+			t.Error(err)
+		}
+	}()
+	var fromChan []remainSample
+	for s := range c {
+		fromChan = append(fromChan, *s)
+	}
+	if !reflect.DeepEqual(fromChan, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, fromChan)
+	}
+
+	um, err := NewUnmarshaller(csv.NewReader(strings.NewReader(in)), remainSample{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := um.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v, expected[0]) {
+		t.Fatalf("expected %+v, got %+v", expected[0], v)
+	}
+}
+
+func TestRemainDecodeWithoutUnmatchedColumns(t *testing.T) {
+	var samples []remainSample
+	if err := UnmarshalString("id,name\n1,a", &samples); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Extra != nil {
+		t.Fatalf("expected no map when every column matched, got %v", samples[0].Extra)
+	}
+}
+
+func TestRemainEncode(t *testing.T) {
+	samples := []remainSample{
+		{ID: 1, Name: "a", Extra: map[string]string{"size": "L", "color": "red"}},
+		{ID: 2, Name: "b", Extra: map[string]string{"weight": "3"}},
+		{ID: 3, Name: "c"},
+	}
+	out, err := MarshalString(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "id,name,color,size,weight\n1,a,red,L,\n2,b,,,3\n3,c,,,\n"
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+
+	// round trip
+	var decoded []remainSample
+	if err := UnmarshalString(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := MarshalString(decoded); err != nil || again != expected {
+		t.Fatalf("expected %q, got %q (%v)", expected, again, err)
+	}
+
+	b := strings.Builder{}
+	enc, err := NewEncoder[remainSample](&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range samples {
+		if err := enc.Encode(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "id,name,color,size\n1,a,red,L\n2,b,,\n3,c,,\n"; b.String() != expected {
+		t.Fatalf("expected the columns of the first value, %q, got %q", expected, b.String())
+	}
+}
+
+func TestRemainInvalidType(t *testing.T) {
+	type sample struct {
+		ID    int    `csv:"id"`
+		Extra string `csv:",remain"`
+	}
+	var samples []sample
+	err := UnmarshalString("id,other\n1,x", &samples)
+	if err == nil || !strings.Contains(err.Error(), "not a map[string]string") {
+		t.Fatalf("expected an invalid type error, got %v", err)
+	}
+}
+
+func TestRemainColumnName(t *testing.T) {
+	type sample struct {
+		ID     int    `csv:"id"`
+		Remain string `csv:"remain"`
+	}
+	var samples []sample
+	if err := UnmarshalString("id,remain\n1,x", &samples); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Remain != "x" {
+		t.Fatalf("expected the remain column to be mapped, got %+v", samples[0])
+	}
+}
+
+func TestRemainDecodeWithHeaderNormalizer(t *testing.T) {
+	in := "ID,Color,Name,Unit Size\n1,red,a,L\n"
+	opts := []Option{WithHeaderNormalizer(strings.ToLower)}
+	var samples []remainSample
+	if err := UnmarshalString(in, &samples, opts...); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"Color": "red", "Unit Size": "L"}
+	if !reflect.DeepEqual(samples[0].Extra, expected) {
+		t.Fatalf("expected the raw header names %v, got %v", expected, samples[0].Extra)
+	}
+
+	um, err := NewUnmarshaller(csv.NewReader(strings.NewReader(in)), remainSample{}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := um.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extra := v.(remainSample).Extra; !reflect.DeepEqual(extra, expected) {
+		t.Fatalf("expected the raw header names %v, got %v", expected, extra)
+	}
+
+	// round trip
+	out, err := MarshalString(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "id,name,Color,Unit Size\n1,a,red,L\n"; out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+}
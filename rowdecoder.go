@@ -0,0 +1,169 @@
+package gocsv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// errNoRecord is returned by RowDecoder.Decode when Next was not called, or returned false.
+var errNoRecord = errors.New("no record to decode, Next must be called first")
+
+// RowDecoder reads CSV records one at a time, in the style of bufio.Scanner and
+// sql.Rows, without goroutines nor channels:
+//
+//	d, err := gocsv.NewRowDecoder(file)
+//	...
+//	for d.Next() {
+//		var c Client
+//		if err := d.Decode(&c); err != nil {
+//			...
+//		}
+//	}
+//	if err := d.Err(); err != nil {
+//		...
+//	}
+//
+// The header row is read and matched once per struct type. In collect mode
+// (see WithCollectErrors), the errors of Decode are gathered and returned by
+// Err once every record was read.
+type RowDecoder struct {
+	cfg        *Config
+	decoder    SimpleDecoder
+	rawHeaders []string // Headers as read, normalized by each converter
+	converters map[reflect.Type]*rowConverter
+	collector  *errorCollector // Set in collect mode, shared by the converters
+	record     []string
+	line       int
+	err        error
+}
+
+// NewRowDecoder creates a RowDecoder reading from in. The header row is read immediately.
+func NewRowDecoder(in io.Reader, opts ...Option) (*RowDecoder, error) {
+	cfg := newConfig(opts)
+	return newRowDecoder(cfg, newSimpleDecoderFromReader(cfg, in))
+}
+
+// NewRowDecoderFromSimpleDecoder creates a RowDecoder reading from the given SimpleDecoder.
+func NewRowDecoderFromSimpleDecoder(in SimpleDecoder, opts ...Option) (*RowDecoder, error) {
+	return newRowDecoder(newConfig(opts), in)
+}
+
+func newRowDecoder(cfg *Config, in SimpleDecoder) (*RowDecoder, error) {
+	headers, err := in.GetCSVRow()
+	if err == io.EOF {
+		return nil, ErrEmptyCSVFile
+	} else if err != nil {
+		return nil, err
+	}
+	d := &RowDecoder{
+		cfg:        cfg,
+		decoder:    in,
+		rawHeaders: headers,
+		converters: make(map[reflect.Type]*rowConverter),
+		line:       1 + preambleLines(in),
+	}
+	if cfg.CollectErrors {
+		d.collector = &errorCollector{max: cfg.MaxErrors}
+	}
+	return d, nil
+}
+
+// Headers returns the header row, as read.
+func (d *RowDecoder) Headers() []string {
+	return append([]string(nil), d.rawHeaders...)
+}
+
+// Line returns the line of the current record, counting the header and the
+// preamble lines skipped before it.
+func (d *RowDecoder) Line() int {
+	return d.line
+}
+
+// Next reads the next record, which can then be decoded with Decode. It
+// returns false once the input is exhausted, or on error; Err then tells which.
+func (d *RowDecoder) Next() bool {
+	d.record = nil
+	if d.err != nil {
+		return false
+	}
+	record, err := d.decoder.GetCSVRow()
+	if err == io.EOF {
+		if d.collector != nil {
+			d.err = d.collector.err()
+		}
+		if d.err == nil {
+			d.err = io.EOF
+		}
+		return false
+	} else if err != nil {
+		d.err = err
+		return false
+	}
+	d.line++
+	d.record = record
+	return true
+}
+
+// Decode decodes the current record into dst, a non-nil pointer to a struct.
+// The struct is reset to its zero value first, so that dst can be reused from
+// one record to the next without keeping the values of the previous record.
+//
+// An error decoding a cell does not stop the reading: Next can be called
+// again. In collect mode, the error is recorded for Err instead, unless there
+// are more than Config.MaxErrors, in which case the reading stops.
+func (d *RowDecoder) Decode(dst interface{}) error {
+	if d.record == nil {
+		return errNoRecord
+	}
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode into %T, only non-nil pointer to struct supported", dst)
+	}
+	converter, err := d.getConverter(v.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	var failures []cellFailure
+	if object, ok := dst.(TypeUnmarshalCSVWithFields); ok {
+		failures = converter.decodeWithFields(object, d.record, d.line)
+	} else {
+		outInner := v.Elem()
+		if failures, err = converter.decodeFields(&outInner, d.record, d.line); err != nil {
+			return err
+		}
+	}
+	if err := converter.handleFailures(failures); err != nil {
+		if d.collector != nil { // too many errors
+			d.err = err
+		}
+		return err
+	}
+	return nil
+}
+
+// Err returns the error that stopped Next, or nil once every record was read.
+// In collect mode, it returns the errors collected by Decode as a *DecodeErrors.
+func (d *RowDecoder) Err() error {
+	if d.err == io.EOF {
+		return nil
+	}
+	return d.err
+}
+
+// getConverter returns the converter of the struct type t, creating it on first use.
+func (d *RowDecoder) getConverter(t reflect.Type) (*rowConverter, error) {
+	if converter, ok := d.converters[t]; ok {
+		return converter, nil
+	}
+	converter, err := newRowConverter(d.cfg, t, d.rawHeaders, nil)
+	if err != nil {
+		return nil, err
+	}
+	converter.collector = d.collector
+	d.converters[t] = converter
+	return converter, nil
+}
@@ -0,0 +1,23 @@
+//go:build go1.23
+
+package gocsv
+
+import (
+	"iter"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalSeqRange(t *testing.T) {
+	var seq iter.Seq2[Sample, error] = UnmarshalSeq[Sample](strings.NewReader("foo,BAR\na,1\nb,2"))
+	var got []string
+	for s, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s.Foo)
+	}
+	if strings.Join(got, ",") != "a,b" {
+		t.Fatalf("unexpected samples %v", got)
+	}
+}
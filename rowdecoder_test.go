@@ -0,0 +1,143 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRowDecoder(t *testing.T) {
+	d, err := NewRowDecoder(strings.NewReader("foo,BAR,Baz\nf,1,x\ne,BAD_INPUT,y\ng,3,"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headers := d.Headers(); !reflect.DeepEqual(headers, []string{"foo", "BAR", "Baz"}) {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+	if err := d.Decode(&Sample{}); err == nil {
+		t.Fatal("expected an error decoding before Next")
+	}
+
+	var s Sample
+	var got []Sample
+	var lines []int
+	for d.Next() {
+		if err := d.Decode(&s); err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) || parseErr.Line != 3 || parseErr.Column != 2 {
+				t.Fatalf("expected an error on line 3 column 2, got %v", err)
+			}
+			continue
+		}
+		got = append(got, s)
+		lines = append(lines, d.Line())
+	}
+	if err := d.Err(); err != nil {
+		t.Fatal(err)
+	}
+	// the struct is reused, and reset before each record
+	expected := []Sample{{Foo: "f", Bar: 1, Baz: "x"}, {Foo: "g", Bar: 3}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+	if !reflect.DeepEqual(lines, []int{2, 4}) {
+		t.Fatalf("unexpected lines %v", lines)
+	}
+	if d.Next() {
+		t.Fatal("expected Next to return false at the end")
+	}
+}
+
+func TestRowDecoderReuse(t *testing.T) {
+	type sample struct {
+		Name  string            `csv:"name"`
+		Score *int              `csv:"score,omitempty"`
+		Tags  []string          `csv:"tags"`
+		Extra map[string]string `csv:",remain"`
+	}
+	d, err := NewRowDecoder(strings.NewReader("name,score,a,b\nx,3,1,2\ny,,,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s sample
+	var got []sample
+	for d.Next() {
+		if err := d.Decode(&s); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s)
+	}
+	if err := d.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Score == nil || *got[0].Score != 3 || got[0].Extra["a"] != "1" {
+		t.Fatalf("unexpected first record %+v", got)
+	}
+	if got[1].Name != "y" || got[1].Score != nil || got[1].Extra["a"] != "" || got[1].Extra["b"] != "" {
+		t.Fatalf("unexpected second record %+v", got[1])
+	}
+}
+
+func TestRowDecoderErrors(t *testing.T) {
+	if _, err := NewRowDecoder(strings.NewReader("")); err != ErrEmptyCSVFile {
+		t.Fatalf("expected ErrEmptyCSVFile, got %v", err)
+	}
+
+	d, err := NewRowDecoder(strings.NewReader("foo,BAR\na,x\nb,2\nc,y"), WithCollectErrors(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for d.Next() {
+		var s Sample
+		if err := d.Decode(s); err == nil {
+			t.Fatal("expected an error decoding into a non pointer")
+		}
+		if err := d.Decode(&s); err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	var decodeErrs *DecodeErrors
+	if !errors.As(d.Err(), &decodeErrs) || len(decodeErrs.Errors) != 2 {
+		t.Fatalf("expected 2 collected errors, got %v", d.Err())
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 records, got %d", count)
+	}
+}
+
+func TestUnmarshalSeq(t *testing.T) {
+	var got []*Sample
+	UnmarshalSeq[*Sample](strings.NewReader("foo,BAR\na,1\nb,BAD\nc,3"))(func(s *Sample, err error) bool {
+		if err != nil {
+			return true
+		}
+		got = append(got, s)
+		return true
+	})
+	if len(got) != 2 || got[0].Foo != "a" || got[1].Foo != "c" || got[0] == got[1] {
+		t.Fatalf("unexpected samples %+v", got)
+	}
+
+	// stopping early
+	count := 0
+	UnmarshalSeq[Sample](strings.NewReader("foo\na\nb\nc"))(func(s Sample, err error) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Fatalf("expected 1 value, got %d", count)
+	}
+
+	var seqErr error
+	UnmarshalSeq[Sample](strings.NewReader(""))(func(s Sample, err error) bool {
+		seqErr = err
+		return true
+	})
+	if seqErr != ErrEmptyCSVFile {
+		t.Fatalf("expected ErrEmptyCSVFile, got %v", seqErr)
+	}
+}
@@ -3,6 +3,8 @@ package gocsv
 //Wraps around SafeCSVWriter and makes it thread safe.
 import (
 	"encoding/csv"
+	"fmt"
+	"io"
 	"sync"
 )
 
@@ -14,7 +16,11 @@ type CSVWriter interface {
 
 type SafeCSVWriter struct {
 	*csv.Writer
-	m sync.Mutex
+	m       sync.Mutex
+	writer  CSVWriter // Used instead of the csv.Writer when set (cf. NewDialectCSVWriter)
+	out     io.Writer // Output of the csv.Writer, when known
+	dialect Dialect   // Dialect of the csv.Writer, when out is known
+	written bool      // A record was written by the csv.Writer
 }
 
 func NewSafeCSVWriter(original *csv.Writer) *SafeCSVWriter {
@@ -27,12 +33,50 @@ func NewSafeCSVWriter(original *csv.Writer) *SafeCSVWriter {
 func (w *SafeCSVWriter) Write(row []string) error {
 	w.m.Lock()
 	defer w.m.Unlock()
+	if w.writer != nil {
+		return w.writer.Write(row)
+	}
+	w.written = true
 	return w.Writer.Write(row)
 }
 
+// writeQuoted writes row with the given quoting, which encoding/csv cannot
+// apply: a DialectWriter with the same settings is used instead, provided
+// that the output is known and that nothing was written yet.
+func (w *SafeCSVWriter) writeQuoted(row []string, quoting []QuotePolicy) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.writer == nil && w.out != nil && !w.written {
+		dialect := w.dialect
+		dialect.Delimiter, dialect.Separator, dialect.LineTerminator = w.Comma, "", "\n"
+		if w.UseCRLF {
+			dialect.LineTerminator = "\r\n"
+		}
+		w.writer = NewDialectWriter(w.out, dialect)
+	}
+	if writer, ok := w.writer.(quotingWriter); ok {
+		return writer.writeQuoted(row, quoting)
+	}
+	return fmt.Errorf("%w: %T", errQuotingUnsupported, w)
+}
+
 //Override flush
 func (w *SafeCSVWriter) Flush() {
 	w.m.Lock()
-	w.Writer.Flush()
+	if w.writer != nil {
+		w.writer.Flush()
+	} else {
+		w.Writer.Flush()
+	}
 	w.m.Unlock()
 }
+
+//Override error
+func (w *SafeCSVWriter) Error() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.writer != nil {
+		return w.writer.Error()
+	}
+	return w.Writer.Error()
+}
@@ -0,0 +1,251 @@
+package gocsv
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// sniffSampleSize is the maximum number of bytes looked at by Sniff.
+const sniffSampleSize = 16 * 1024
+
+// sniffDelimiters are the delimiters Sniff chooses from, in order of preference.
+var sniffDelimiters = []rune{',', ';', '\t', '|'}
+
+// sniffQuotes are the quote characters Sniff chooses from, in order of preference.
+var sniffQuotes = []rune{'"', '\''}
+
+// SniffResult is the format of a CSV input guessed by Sniff.
+type SniffResult struct {
+	Dialect
+
+	// HasHeader tells whether the first record looks like a header: its cells
+	// are not numbers, or differ in length from the cells below, where the
+	// other records have numbers or cells of a fixed length.
+	HasHeader bool
+}
+
+// Sniff guesses the delimiter (',', ';', '\t' or '|'), the quote character,
+// the line terminator and the presence of a header row of a CSV input from its
+// first bytes: the ones returned by the first read of in, up to 16 KiB, so
+// that a streaming input is not waited for. It returns a reader replaying
+// these bytes followed by the rest of the input, to be decoded instead of in.
+func Sniff(in io.Reader) (SniffResult, io.Reader, error) {
+	r := bufio.NewReaderSize(in, sniffSampleSize)
+	if _, err := r.Peek(1); err != nil && err != io.EOF {
+		return SniffResult{}, r, err
+	}
+	sample, _ := r.Peek(r.Buffered())
+	return sniffSample(string(sample), len(sample) == sniffSampleSize), r, nil
+}
+
+// sniffSample guesses the format of the sample. When truncated, its last line
+// may be incomplete, and is left out.
+func sniffSample(sample string, truncated bool) SniffResult {
+	result := SniffResult{Dialect: Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\n"}, HasHeader: true}
+	if i := strings.IndexAny(sample, "\r\n"); i >= 0 {
+		if strings.HasPrefix(sample[i:], "\r\n") {
+			result.LineTerminator = "\r\n"
+		} else if sample[i] == '\r' {
+			result.LineTerminator = "\r"
+		}
+	}
+	if truncated {
+		if i := strings.LastIndexAny(sample, "\r\n"); i >= 0 {
+			sample = sample[:i+1]
+		}
+	}
+
+	result.Quote = sniffQuote(sample)
+	bestScore := 0.0
+	var bestRecords [][]string
+	for _, delimiter := range sniffDelimiters {
+		records := splitSniffSample(sample, delimiter, result.Quote)
+		if score := delimiterScore(records); score > bestScore {
+			bestScore = score
+			result.Delimiter = delimiter
+			bestRecords = records
+		}
+	}
+	if bestRecords == nil {
+		bestRecords = splitSniffSample(sample, result.Delimiter, result.Quote)
+	}
+	result.HasHeader = looksLikeHeader(bestRecords)
+	return result
+}
+
+// sniffQuote returns the quote character most often found at the edges of fields.
+func sniffQuote(sample string) rune {
+	best, bestCount := sniffQuotes[0], 0
+	for _, quote := range sniffQuotes {
+		count := 0
+		for i, c := range sample {
+			if c != quote {
+				continue
+			}
+			// opening quotes follow a line break or a delimiter
+			if i == 0 || strings.ContainsRune("\r\n,;\t|", rune(sample[i-1])) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = quote, count
+		}
+	}
+	return best
+}
+
+// splitSniffSample splits the sample into records of fields, using the given
+// delimiter and quote character. Empty lines are left out.
+func splitSniffSample(sample string, delimiter, quote rune) [][]string {
+	var records [][]string
+	var record []string
+	var field strings.Builder
+	inQuotes, quoted := false, false
+	endField := func() {
+		record = append(record, field.String())
+		field.Reset()
+		quoted = false
+	}
+	runes := []rune(sample)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case inQuotes && c == quote:
+			if i+1 < len(runes) && runes[i+1] == quote {
+				field.WriteRune(quote)
+				i++
+			} else {
+				inQuotes = false
+			}
+		case inQuotes:
+			field.WriteRune(c)
+		case c == quote && field.Len() == 0 && !quoted:
+			inQuotes, quoted = true, true
+		case c == delimiter:
+			endField()
+		case c == '\r' || c == '\n':
+			if c == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+				i++
+			}
+			if field.Len() > 0 || len(record) > 0 || quoted {
+				endField()
+				records = append(records, record)
+			}
+			record = nil
+		default:
+			field.WriteRune(c)
+		}
+	}
+	if field.Len() > 0 || len(record) > 0 || quoted {
+		endField()
+		records = append(records, record)
+	}
+	return records
+}
+
+// delimiterScore rates how well the records split by a delimiter look like
+// CSV: the more records have the most frequent number of fields, the better,
+// and more fields break the ties. It is 0 when no record has several fields.
+func delimiterScore(records [][]string) float64 {
+	counts := make(map[int]int)
+	for _, record := range records {
+		counts[len(record)]++
+	}
+	mode, modeCount := 0, 0
+	for fields, count := range counts {
+		if count > modeCount || (count == modeCount && fields > mode) {
+			mode, modeCount = fields, count
+		}
+	}
+	if mode < 2 {
+		return 0
+	}
+	consistency := float64(modeCount) / float64(len(records))
+	return consistency + float64(mode)/1000
+}
+
+// looksLikeHeader tells whether the first record looks like a header, voting
+// on each column: a header cell that is not a number above numbers, or whose
+// length differs from the cells below of a fixed length, votes for a header.
+func looksLikeHeader(records [][]string) bool {
+	if len(records) < 2 {
+		return true
+	}
+	header, rows := records[0], records[1:]
+	votes := 0
+	for column, cell := range header {
+		numeric, length := true, -1
+		for _, row := range rows {
+			if column >= len(row) {
+				continue
+			}
+			if !isSniffNumber(row[column]) {
+				numeric = false
+			}
+			if length == -1 {
+				length = len(row[column])
+			} else if length != len(row[column]) {
+				length = -2 // lengths vary
+			}
+		}
+		if numeric {
+			if !isSniffNumber(cell) {
+				votes++
+			} else {
+				votes--
+			}
+		} else if length >= 0 {
+			if len(cell) != length {
+				votes++
+			} else {
+				votes--
+			}
+		}
+	}
+	return votes > 0
+}
+
+// isSniffNumber tells whether s is a number, with a decimal point or comma.
+func isSniffNumber(s string) bool {
+	_, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(s), ",", ".", 1), 64)
+	return err == nil
+}
+
+// getSniffedCSVReader sniffs the format of in, and returns the CSV reader of
+// the config reading it with the sniffed delimiter when it is a *csv.Reader,
+// or reading it with the dialect of the config and the sniffed delimiter and
+// quote. As a csv.Reader only reads '"' quotes, a DialectReader with the
+// settings of the *csv.Reader replaces it when another quote is sniffed.
+func (cfg *Config) getSniffedCSVReader(in io.Reader) CSVReader {
+	result, in, err := Sniff(in)
+	if cfg.Dialect != nil {
+		dialect := *cfg.Dialect
+		if err == nil {
+			dialect.Delimiter, dialect.Separator = result.Delimiter, ""
+			dialect.Quote = result.Quote
+		}
+		return NewDialectCSVReader(in, dialect)
+	}
+	reader := cfg.CSVReader(in)
+	csvReader, ok := reader.(*csv.Reader)
+	if !ok || err != nil {
+		return reader
+	}
+	if result.Quote != '"' {
+		dialectReader := NewDialectReader(in, Dialect{
+			Delimiter:        result.Delimiter,
+			Quote:            result.Quote,
+			LineTerminator:   result.LineTerminator,
+			Comment:          csvReader.Comment,
+			TrimLeadingSpace: csvReader.TrimLeadingSpace,
+			LazyQuotes:       csvReader.LazyQuotes,
+		})
+		dialectReader.FieldsPerRecord = csvReader.FieldsPerRecord
+		return dialectReader
+	}
+	csvReader.Comma = result.Delimiter
+	return reader
+}
@@ -0,0 +1,124 @@
+package gocsv
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		result SniffResult
+	}{
+		{"comma", "name,age\nBob,32\nAlice,27\n", SniffResult{Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\n"}, true}},
+		{"semicolon", "name;price\r\n\"Smith; John\";1,5\r\nDoe;2,25\r\n", SniffResult{Dialect{Delimiter: ';', Quote: '"', LineTerminator: "\r\n"}, true}},
+		{"tab", "a\tb\tc\n1\t2\t3\n4\t5\t6\n", SniffResult{Dialect{Delimiter: '\t', Quote: '"', LineTerminator: "\n"}, true}},
+		{"pipe", "id|name\r1|'Bob, Jr'\r2|'Alice'\r", SniffResult{Dialect{Delimiter: '|', Quote: '\'', LineTerminator: "\r"}, true}},
+		{"no header", "1,Bob\n2,Alice\n3,Carol\n", SniffResult{Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\n"}, false}},
+		{"same length codes", "id,name\nAB12,Bob\nCD34,Alice\n", SniffResult{Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\n"}, true}},
+		{"single column", "name\nBob\n", SniffResult{Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\n"}, true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, r, err := Sniff(strings.NewReader(test.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != test.result {
+				t.Fatalf("expected %+v, got %+v", test.result, result)
+			}
+			replayed, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(replayed) != test.in {
+				t.Fatalf("expected the input to be replayed, got %q", replayed)
+			}
+		})
+	}
+}
+
+func TestSniffLargeInput(t *testing.T) {
+	in := "name;age\n" + strings.Repeat("Bob;32\nAlice, Jr;27\n", 2000)
+	result, r, err := Sniff(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Delimiter != ';' {
+		t.Fatalf("expected ';', got %q", result.Delimiter)
+	}
+	replayed, _ := io.ReadAll(r)
+	if string(replayed) != in {
+		t.Fatal("expected the whole input to be replayed")
+	}
+}
+
+func TestSniffStreamingInput(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.Write([]byte("name;age\nBob;32\nAlice;27\n")) // the rest of the input is not written yet
+	}()
+	result, _, err := Sniff(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Delimiter != ';' {
+		t.Fatalf("expected ';', got %q", result.Delimiter)
+	}
+}
+
+func TestAutoDialectQuote(t *testing.T) {
+	type sample struct {
+		Name string `csv:"name"`
+		Age  int    `csv:"age"`
+	}
+	var samples []sample
+	if err := UnmarshalString("name;age\n'Smith; John';27\n'Bob';32\n", &samples, WithAutoDialect()); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Name != "Smith; John" || samples[1].Name != "Bob" {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+}
+
+func TestAutoDialect(t *testing.T) {
+	type sample struct {
+		Name string  `csv:"name"`
+		Age  int     `csv:"age"`
+		Rate float64 `csv:"rate"`
+	}
+	for _, in := range []string{
+		"name,age,rate\nBob,32,1.5\n\"Smith, John\",27,2\n",
+		"name;age;rate\nBob;32;1.5\nSmith, John;27;2\n",
+		"name\tage\trate\nBob\t32\t1.5\nSmith, John\t27\t2\n",
+		"name|age|rate\r\nBob|32|1.5\r\nSmith, John|27|2\r\n",
+	} {
+		var samples []sample
+		if err := UnmarshalString(in, &samples, WithAutoDialect()); err != nil {
+			t.Fatal(err)
+		}
+		expected := []sample{{"Bob", 32, 1.5}, {"Smith, John", 27, 2}}
+		if len(samples) != 2 || samples[0] != expected[0] || samples[1] != expected[1] {
+			t.Fatalf("%q: unexpected samples %+v", in, samples)
+		}
+	}
+}
+
+func TestAutoDialectPreamble(t *testing.T) {
+	type sample struct {
+		Name string `csv:"name"`
+		Age  int    `csv:"age"`
+	}
+	in := "Report, generated today\n\nname;age\nBob;32\nAlice;27\n"
+
+	var samples []sample
+	if err := UnmarshalString(in, &samples, WithAutoDialect(), WithSkipLines(2)); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[1].Name != "Alice" || samples[1].Age != 27 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+}
@@ -0,0 +1,174 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// ErrNoTrailer is returned when decoding the trailer into a struct (see
+// WithTrailer) while the input has no trailer record.
+var ErrNoTrailer = errors.New("no trailer record found")
+
+// ErrShortTrailerInput is returned when the input holds no more rows than
+// Config.TrailerRecords: no header row nor record would be left before the
+// trailer.
+var ErrShortTrailerInput = errors.New("input shorter than trailer")
+
+// hasTrailer tells whether the config sets records apart at the end of the input.
+func (cfg *Config) hasTrailer() bool {
+	return cfg.TrailerRecords > 0 || cfg.TrailerStart != nil
+}
+
+// trailerRecord is a record read ahead by a trailerDecoder, with its read error.
+type trailerRecord struct {
+	record []string
+	err    error
+}
+
+// trailerDecoder is a SimpleDecoder setting the trailer records apart, as told
+// by the config. They are given to Config.TrailerHandler once the input is read.
+type trailerDecoder struct {
+	cfg     *Config
+	decoder SimpleDecoder
+	pending []trailerRecord // Records read ahead, the trailer once the input is read
+	read    int             // Number of rows read from the decoder
+	done    bool            // The input is read, and the trailer was handled
+	err     error
+}
+
+func newTrailerDecoder(cfg *Config, decoder SimpleDecoder) *trailerDecoder {
+	return &trailerDecoder{cfg: cfg, decoder: decoder}
+}
+
+// GetCSVRow returns the next record, unless it belongs to the trailer. At the
+// end of the records, the trailer is handled, and io.EOF is returned.
+func (t *trailerDecoder) GetCSVRow() ([]string, error) {
+	for {
+		if t.err != nil {
+			return nil, t.err
+		}
+		if len(t.pending) > 0 && (t.done || len(t.pending) > t.cfg.TrailerRecords) {
+			next := t.pending[0]
+			t.pending = t.pending[1:]
+			return next.record, next.err
+		}
+		if t.done {
+			return nil, io.EOF
+		}
+		record, err := t.decoder.GetCSVRow()
+		if err == io.EOF {
+			t.endOfRecords()
+			continue
+		} else if err != nil && (record == nil || !errors.Is(err, csv.ErrFieldCount)) {
+			// a record with an unexpected number of fields may belong to the
+			// trailer, its error is only returned if it is not the case
+			return nil, err
+		}
+		t.read++
+		t.pending = append(t.pending, trailerRecord{record, err})
+		if t.cfg.TrailerStart != nil && t.cfg.TrailerStart(record) {
+			if err := t.readTrailer(); err != nil {
+				return nil, err
+			}
+			t.endOfRecords()
+		}
+	}
+}
+
+func (t *trailerDecoder) GetCSVRows() ([][]string, error) {
+	var records [][]string
+	for {
+		record, err := t.GetCSVRow()
+		if err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+// readTrailer reads the remaining records, which belong to the trailer started
+// by the last pending record. The records before it are not part of the trailer.
+func (t *trailerDecoder) readTrailer() error {
+	for {
+		record, err := t.decoder.GetCSVRow()
+		if err == io.EOF {
+			return nil
+		} else if err != nil && (record == nil || !errors.Is(err, csv.ErrFieldCount)) {
+			return err
+		}
+		t.read++
+		t.pending = append(t.pending, trailerRecord{record, err})
+	}
+}
+
+// endOfRecords sets the trailer apart from the pending records, and hands it to
+// Config.TrailerHandler. The trailer starts at the first record matched by
+// Config.TrailerStart, or with the last Config.TrailerRecords records if
+// earlier. The pending records before it are still to be returned. A
+// non-empty input holding no more rows than Config.TrailerRecords is an
+// error, as its header row would be taken for the trailer.
+func (t *trailerDecoder) endOfRecords() {
+	t.done = true
+	if t.read > 0 && t.read <= t.cfg.TrailerRecords {
+		t.err = ErrShortTrailerInput
+		return
+	}
+	start := len(t.pending) - t.cfg.TrailerRecords
+	if t.cfg.TrailerStart != nil {
+		for i, pending := range t.pending {
+			if i < start && t.cfg.TrailerStart(pending.record) {
+				start = i
+				break
+			}
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	trailer := make([][]string, 0, len(t.pending)-start)
+	for _, pending := range t.pending[start:] {
+		trailer = append(trailer, pending.record)
+	}
+	t.pending = t.pending[:start]
+	if t.cfg.TrailerHandler != nil {
+		t.err = t.cfg.TrailerHandler(trailer)
+	}
+}
+
+// decodeTrailer decodes the trailer records into out, a pointer to a slice of
+// structs, or a pointer to a struct for a trailer of a single record. The
+// columns are mapped to the fields by position, like in UnmarshalWithoutHeaders.
+func decodeTrailer(cfg *Config, records [][]string, out interface{}) error {
+	outValue := reflect.ValueOf(out)
+	if outValue.Kind() != reflect.Ptr || outValue.IsNil() {
+		return fmt.Errorf("cannot decode the trailer into %T, only non-nil pointer supported", out)
+	}
+	outValue = outValue.Elem()
+	if outValue.Kind() == reflect.Slice {
+		if len(records) == 0 {
+			return nil
+		}
+		return readToWithoutHeaders(cfg, trailerRecords(records), out)
+	}
+	if len(records) == 0 {
+		return ErrNoTrailer
+	}
+	slice := reflect.New(reflect.SliceOf(outValue.Type()))
+	if err := readToWithoutHeaders(cfg, trailerRecords(records[:1]), slice.Interface()); err != nil {
+		return err
+	}
+	outValue.Set(slice.Elem().Index(0))
+	return nil
+}
+
+// trailerRecords is a Decoder returning the given records.
+type trailerRecords [][]string
+
+func (r trailerRecords) GetCSVRows() ([][]string, error) {
+	return r, nil
+}
@@ -0,0 +1,106 @@
+package gocsv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type trailerTotals struct {
+	Label string `csv:"#1"`
+	Count int    `csv:"#2"`
+}
+
+func TestTrailerRecords(t *testing.T) {
+	in := "foo,BAR\na,1\nb,2\nTOTAL,2\nEND OF FILE\n"
+	var trailer [][]string
+	var samples []Sample
+	err := UnmarshalString(in, &samples, WithTrailerRecords(2), WithTrailerHandler(func(records [][]string) error {
+		trailer = records
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[1].Foo != "b" {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	expected := [][]string{{"TOTAL", "2"}, {"END OF FILE"}}
+	if !reflect.DeepEqual(trailer, expected) {
+		t.Fatalf("expected trailer %q, got %q", expected, trailer)
+	}
+}
+
+func TestTrailerStart(t *testing.T) {
+	in := "foo,BAR\na,1\nb,2\nTOTAL,2\nEND OF FILE\n"
+	isTrailer := func(record []string) bool { return record[0] == "TOTAL" }
+
+	var totals trailerTotals
+	c := make(chan Sample, 10)
+	if err := UnmarshalToChan(strings.NewReader(in), c, WithTrailerStart(isTrailer), WithTrailer(&totals)); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for range c {
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 samples, got %d", count)
+	}
+	if totals != (trailerTotals{Label: "TOTAL", Count: 2}) {
+		t.Fatalf("unexpected trailer %+v", totals)
+	}
+
+	var all []trailerTotals
+	var samples []Sample
+	if err := UnmarshalString("foo,BAR\na,1\nTOTAL,1\nEND,0\n", &samples, WithTrailerStart(isTrailer), WithTrailer(&all)); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[1].Label != "END" {
+		t.Fatalf("unexpected trailer %+v", all)
+	}
+
+	// the trailer handler error is returned
+	mismatch := errors.New("count mismatch")
+	err := UnmarshalString(in, &samples, WithTrailerStart(isTrailer), WithTrailerHandler(func(records [][]string) error {
+		return mismatch
+	}))
+	if !errors.Is(err, mismatch) {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+
+	// a missing trailer is reported when decoding it into a struct
+	err = UnmarshalString("foo,BAR\na,1\n", &samples, WithTrailerStart(isTrailer), WithTrailer(&totals))
+	if !errors.Is(err, ErrNoTrailer) {
+		t.Fatalf("expected ErrNoTrailer, got %v", err)
+	}
+}
+
+func TestTrailerFieldCount(t *testing.T) {
+	// records with an unexpected number of fields are still errors outside of the trailer
+	var samples []Sample
+	err := UnmarshalString("foo,BAR\na\nb,2\nEND\n", &samples, WithTrailerRecords(1))
+	if err == nil || !strings.Contains(err.Error(), "wrong number of fields") {
+		t.Fatalf("expected a field count error, got %v", err)
+	}
+}
+
+func TestTrailerRecordsShortInput(t *testing.T) {
+	for _, in := range []string{"foo,BAR\n", "foo,BAR\nTOTAL,2\n"} {
+		var samples []Sample
+		err := UnmarshalString(in, &samples, WithTrailerRecords(2))
+		if !errors.Is(err, ErrShortTrailerInput) {
+			t.Fatalf("%q: expected ErrShortTrailerInput, got %v", in, err)
+		}
+	}
+
+	// a header followed by the trailer only
+	var samples []Sample
+	if err := UnmarshalString("foo,BAR\nTOTAL,2\n", &samples, WithTrailerRecords(1)); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 0 {
+		t.Fatalf("expected no sample, got %+v", samples)
+	}
+}
@@ -1,11 +1,14 @@
 package gocsv
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"encoding"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"encoding/json"
 )
@@ -18,6 +21,8 @@ var (
 	textMarshalerType          = reflect.TypeOf(new(encoding.TextMarshaler)).Elem()
 	unmarshalerType            = reflect.TypeOf(new(TypeUnmarshaller)).Elem()
 	unmarshalCSVWithFieldsType = reflect.TypeOf(new(TypeUnmarshalCSVWithFields)).Elem()
+	scannerType                = reflect.TypeOf(new(sql.Scanner)).Elem()
+	valuerType                 = reflect.TypeOf(new(driver.Valuer)).Elem()
 )
 
 // TypeMarshaller is implemented by any value that has a MarshalCSV method
@@ -206,9 +211,18 @@ func toFloat(in interface{}) (float64, error) {
 	return 0, fmt.Errorf("No known conversion from " + inValue.Type().String() + " to float")
 }
 
-func setField(field reflect.Value, value string, omitEmpty bool) error {
+func setField(field reflect.Value, value string, fieldInfo *fieldInfo) error {
+	if fieldInfo.tagErr != nil {
+		return fieldInfo.tagErr
+	}
+	if fieldInfo.isMapEntry(field) {
+		return setMapEntry(field, value, fieldInfo)
+	}
+	if fieldInfo.isNull(value) {
+		return setNull(field)
+	}
 	if field.Kind() == reflect.Ptr {
-		if omitEmpty && value == "" {
+		if fieldInfo.omitEmpty && value == "" {
 			return nil
 		}
 		if field.IsNil() {
@@ -216,8 +230,20 @@ func setField(field reflect.Value, value string, omitEmpty bool) error {
 		}
 		field = field.Elem()
 	}
+	if fieldInfo.isSplit(field) {
+		return setSplitField(field, value, fieldInfo)
+	}
 
 	switch field.Interface().(type) {
+	case time.Time:
+		if !fieldInfo.hasTimeOptions() {
+			return unmarshall(field, value)
+		}
+		t, err := toTime(value, fieldInfo)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
 	case string:
 		s, err := toString(value)
 		if err != nil {
@@ -305,16 +331,30 @@ func setField(field reflect.Value, value string, omitEmpty bool) error {
 	return nil
 }
 
-func getFieldAsString(field reflect.Value) (str string, err error) {
+func getFieldAsString(field reflect.Value, fieldInfo *fieldInfo) (str string, err error) {
+	if fieldInfo.tagErr != nil {
+		return "", fieldInfo.tagErr
+	}
+	if fieldInfo.isMapEntry(field) {
+		return getMapEntryAsString(field, fieldInfo)
+	}
+	if isNullValue(field) {
+		return fieldInfo.nullToken(), nil
+	}
+	if fieldInfo.isSplit(field) {
+		return getSplitFieldAsString(field, fieldInfo)
+	}
 	switch field.Kind() {
 	case reflect.Interface, reflect.Ptr:
-		if field.IsNil() {
-			return "", nil
-		}
-		return getFieldAsString(field.Elem())
+		return getFieldAsString(field.Elem(), fieldInfo)
 	default:
 		// Check if field is go native type
 		switch field.Interface().(type) {
+		case time.Time:
+			if fieldInfo.hasTimeOptions() {
+				return fromTime(field.Interface().(time.Time), fieldInfo), nil
+			}
+			return marshall(field)
 		case string:
 			return field.String(), nil
 		case bool:
@@ -395,6 +435,242 @@ func getFieldAsString(field reflect.Value) (str string, err error) {
 	return str, nil
 }
 
+// hasTimeOptions tells whether a layout or a time zone was given in the tag of the field.
+func (f *fieldInfo) hasTimeOptions() bool {
+	return len(f.timeLayouts) > 0 || f.location != nil
+}
+
+// toTime parses value with the layouts of the field, trying each in turn, and
+// defaulting to RFC 3339. Values without an offset are read in the time zone
+// of the field, UTC if none. An empty value is the zero time, as is the zero
+// time written by fromTime.
+func toTime(value string, fieldInfo *fieldInfo) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	layouts := fieldInfo.timeLayouts
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339Nano}
+	}
+	location := fieldInfo.location
+	if location == nil {
+		location = time.UTC
+	}
+	var firstErr error
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, value, location)
+		if err == nil {
+			if t.Equal(time.Date(1, time.January, 1, 0, 0, 0, 0, t.Location())) {
+				return time.Time{}, nil
+			}
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, firstErr
+}
+
+// fromTime formats t in the time zone of the field with its first layout,
+// RFC 3339 if none. The zero time is formatted like any other time, as without
+// layout (e.g. 0001-01-01T00:00:00Z), but in UTC whatever the time zone of the
+// field, so that toTime reads it back as the zero time.
+func fromTime(t time.Time, fieldInfo *fieldInfo) string {
+	if fieldInfo.location != nil && !t.IsZero() {
+		t = t.In(fieldInfo.location)
+	}
+	if len(fieldInfo.timeLayouts) == 0 {
+		return t.Format(time.RFC3339Nano)
+	}
+	return t.Format(fieldInfo.timeLayouts[0])
+}
+
+// isNull tells whether value is one of the null tokens of the field.
+func (f *fieldInfo) isNull(value string) bool {
+	for _, token := range f.nullTokens {
+		if value == token {
+			return true
+		}
+	}
+	return false
+}
+
+// nullToken returns the value written for a null field: its first null token, or an empty string.
+func (f *fieldInfo) nullToken() string {
+	if len(f.nullTokens) == 0 {
+		return ""
+	}
+	return f.nullTokens[0]
+}
+
+// setNull sets field to its null value: nil for pointers, Valid=false for
+// sql.Null* types (through sql.Scanner), the zero value otherwise.
+func setNull(field reflect.Value) error {
+	if field.Kind() != reflect.Ptr && field.CanAddr() {
+		if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(nil)
+		}
+	}
+	field.Set(reflect.Zero(field.Type()))
+	return nil
+}
+
+// isNullValue tells whether field holds a null value: a nil pointer or
+// interface, or a driver.Valuer such as sql.NullString whose value is nil.
+func isNullValue(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if field.IsNil() {
+			return true
+		}
+	}
+	if field.CanInterface() {
+		if valuer, ok := field.Interface().(driver.Valuer); ok {
+			v, err := valuer.Value()
+			return err == nil && v == nil
+		}
+	}
+	return false
+}
+
+// fromDriverValue formats a value returned by driver.Valuer.
+func fromDriverValue(v driver.Value) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case []byte:
+		return string(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+	return toString(v)
+}
+
+// isSplit tells whether field is a slice or an array held in a single cell,
+// its elements being separated by the split tag option.
+func (f *fieldInfo) isSplit(field reflect.Value) bool {
+	if f.split == "" {
+		return false
+	}
+	return field.Kind() == reflect.Slice || field.Kind() == reflect.Array
+}
+
+// elemInfo returns the options used for each element of a split field.
+func (f *fieldInfo) elemInfo() *fieldInfo {
+	elemInfo := *f
+	elemInfo.split = ""
+	elemInfo.omitEmpty = false
+	return &elemInfo
+}
+
+// isMapEntry tells whether the column of fieldInfo holds an entry of the prefix map field.
+func (f *fieldInfo) isMapEntry(field reflect.Value) bool {
+	return f.mapKey != "" && field.Kind() == reflect.Map
+}
+
+// setMapEntry converts value to the element type of the map field, and stores
+// it under the key of the column. Empty and null values are not stored.
+func setMapEntry(field reflect.Value, value string, fieldInfo *fieldInfo) error {
+	if value == "" || fieldInfo.isNull(value) {
+		return nil
+	}
+	if field.IsNil() {
+		field.Set(reflect.MakeMap(field.Type()))
+	}
+	elem := reflect.New(field.Type().Elem()).Elem()
+	elemInfo := *fieldInfo
+	elemInfo.mapKey = ""
+	if err := setField(elem, value, &elemInfo); err != nil {
+		return err
+	}
+	field.SetMapIndex(reflect.ValueOf(fieldInfo.mapKey).Convert(field.Type().Key()), elem)
+	return nil
+}
+
+// getMapEntryAsString returns the entry of the map field for the key of the
+// column, the null token if there is none.
+func getMapEntryAsString(field reflect.Value, fieldInfo *fieldInfo) (string, error) {
+	elem := field.MapIndex(reflect.ValueOf(fieldInfo.mapKey).Convert(field.Type().Key()))
+	if !elem.IsValid() {
+		return fieldInfo.nullToken(), nil
+	}
+	elemInfo := *fieldInfo
+	elemInfo.mapKey = ""
+	return getFieldAsString(elem, &elemInfo)
+}
+
+// setSplitField splits value into the elements of the slice or array field.
+// An empty value is an empty slice.
+func setSplitField(field reflect.Value, value string, fieldInfo *fieldInfo) error {
+	var parts []string
+	if value != "" {
+		parts = splitEscaped(value, fieldInfo.split)
+	}
+	if field.Kind() == reflect.Slice {
+		field.Set(reflect.MakeSlice(field.Type(), len(parts), len(parts)))
+	} else if len(parts) > field.Len() {
+		return fmt.Errorf("cannot store %d values in %s", len(parts), field.Type())
+	}
+	elemInfo := fieldInfo.elemInfo()
+	for i, part := range parts {
+		if err := setField(field.Index(i), part, elemInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSplitFieldAsString joins the elements of the slice or array field.
+func getSplitFieldAsString(field reflect.Value, fieldInfo *fieldInfo) (string, error) {
+	elemInfo := fieldInfo.elemInfo()
+	parts := make([]string, field.Len())
+	for i := range parts {
+		part, err := getFieldAsString(field.Index(i), elemInfo)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = escapeSeparator(part, fieldInfo.split)
+	}
+	return strings.Join(parts, fieldInfo.split), nil
+}
+
+// splitEscaped splits s around sep, which is kept in a part when preceded by
+// a backslash. A backslash is written as two backslashes.
+func splitEscaped(s, sep string) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			if strings.HasPrefix(s[i+1:], sep) {
+				part.WriteString(sep)
+				i += 1 + len(sep)
+			} else {
+				part.WriteByte(s[i+1])
+				i += 2
+			}
+		case strings.HasPrefix(s[i:], sep):
+			parts = append(parts, part.String())
+			part.Reset()
+			i += len(sep)
+		default:
+			part.WriteByte(s[i])
+			i++
+		}
+	}
+	return append(parts, part.String())
+}
+
+// escapeSeparator escapes the backslashes and the occurrences of sep in s, as read by splitEscaped.
+func escapeSeparator(s, sep string) string {
+	if !strings.Contains(s, "\\") && !strings.Contains(s, sep) {
+		return s
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\\", "\\\\"), sep, "\\"+sep)
+}
+
 // --------------------------------------------------------------------------
 // Un/serializations helpers
 
@@ -408,14 +684,25 @@ func canMarshal(t reflect.Type) bool {
 	}
 
 	// Pointer to a struct that implements any of the text or CSV marshaling interfaces
-	t = reflect.PtrTo(t)
-	if t.Implements(marshalerType) ||
-		t.Implements(textMarshalerType) ||
-		t.Implements(unmarshalerType) ||
-		t.Implements(unmarshalCSVWithFieldsType) {
+	pt := reflect.PtrTo(t)
+	if pt.Implements(marshalerType) ||
+		pt.Implements(textMarshalerType) ||
+		pt.Implements(unmarshalerType) ||
+		pt.Implements(unmarshalCSVWithFieldsType) {
 		return true
 	}
-	return false
+
+	// sql.Null* types, through sql.Scanner and driver.Valuer: the other structs
+	// implementing them are still expanded into nested columns
+	return isSQLNullType(t)
+}
+
+// isSQLNullType tells whether t is a type of database/sql, like sql.NullString,
+// read and written as a single value through sql.Scanner and driver.Valuer.
+func isSQLNullType(t reflect.Type) bool {
+	return t.PkgPath() == "database/sql" &&
+		(t.Implements(scannerType) || reflect.PtrTo(t).Implements(scannerType) ||
+			t.Implements(valuerType) || reflect.PtrTo(t).Implements(valuerType))
 }
 
 func unmarshall(field reflect.Value, value string) error {
@@ -434,6 +721,15 @@ func unmarshall(field reflect.Value, value string) error {
 			if ok {
 				return fieldTextUnmarshaler.UnmarshalText([]byte(value))
 			}
+
+			// Otherwise try to use sql.Scanner, an empty value being null
+			fieldScanner, ok := fieldIface.(sql.Scanner)
+			if ok {
+				if value == "" {
+					return fieldScanner.Scan(nil)
+				}
+				return fieldScanner.Scan(value)
+			}
 		}
 
 		return NoUnmarshalFuncError{field.Type()}
@@ -471,6 +767,16 @@ func marshall(field reflect.Value) (value string, err error) {
 				return string(text), err
 			}
 
+			// Otherwise try to use driver.Valuer
+			fieldValuer, ok := fieldIface.(driver.Valuer)
+			if ok {
+				v, err := fieldValuer.Value()
+				if err != nil {
+					return "", err
+				}
+				return fromDriverValue(v)
+			}
+
 			// Otherwise try to use Stringer
 			fieldStringer, ok := fieldIface.(fmt.Stringer)
 			if ok {
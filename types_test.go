@@ -1,8 +1,12 @@
 package gocsv
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type sampleTypeUnmarshaller struct {
@@ -45,7 +49,7 @@ func (s customStringAlias) MarshalCSV() (string, error) {
 }
 
 func Test_getFieldAsString_CustomStringAlias(t *testing.T) {
-	s, err := getFieldAsString(reflect.ValueOf(customStringAlias("foo")))
+	s, err := getFieldAsString(reflect.ValueOf(customStringAlias("foo")), &fieldInfo{})
 	if err != nil {
 		t.Fatalf("getFieldAsString failure: %s", err)
 	}
@@ -54,7 +58,7 @@ func Test_getFieldAsString_CustomStringAlias(t *testing.T) {
 		t.Fatalf(`expected "foo" got %s`, s)
 	}
 
-	s, err = getFieldAsString(reflect.ValueOf(stringAlias("foo")))
+	s, err = getFieldAsString(reflect.ValueOf(stringAlias("foo")), &fieldInfo{})
 	if err != nil {
 		t.Fatalf("getFieldAsString failure: %s", err)
 	}
@@ -139,3 +143,241 @@ func TestToInt(t *testing.T) {
 		}
 	}
 }
+
+type timeLayoutSample struct {
+	Created  time.Time  `csv:"created,layout=2006-01-02 15:04,layout=02/01/2006,tz=Europe/Paris"`
+	Shipped  *time.Time `csv:"shipped,omitempty,layout=2006-01-02"`
+	Received time.Time  `csv:"received"`
+}
+
+func TestTimeLayoutTags(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("time zone database not available:", err)
+	}
+	in := `created,shipped,received
+2024-03-01 10:30,2024-03-02,2024-03-03T08:00:00Z
+25/12/2023,,2024-03-03T08:00:00Z`
+	var samples []timeLayoutSample
+	if err := UnmarshalString(in, &samples); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+	if expected := time.Date(2024, 3, 1, 10, 30, 0, 0, paris); !samples[0].Created.Equal(expected) {
+		t.Fatalf("expected %v, got %v", expected, samples[0].Created)
+	}
+	if expected := time.Date(2023, 12, 25, 0, 0, 0, 0, paris); !samples[1].Created.Equal(expected) {
+		t.Fatalf("expected the fallback layout to give %v, got %v", expected, samples[1].Created)
+	}
+	if samples[0].Shipped == nil || !samples[0].Shipped.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected shipped date %v", samples[0].Shipped)
+	}
+	if samples[1].Shipped != nil {
+		t.Fatalf("expected an empty cell to leave the omitempty pointer nil, got %v", samples[1].Shipped)
+	}
+
+	samples[1].Created = samples[1].Created.UTC()
+	out, err := MarshalString(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `created,shipped,received
+2024-03-01 10:30,2024-03-02,2024-03-03T08:00:00Z
+2023-12-25 00:00,,2024-03-03T08:00:00Z
+`
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+}
+
+func TestTimeLayoutTagsZeroTime(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Paris"); err != nil {
+		t.Skip("time zone database not available:", err)
+	}
+	// the zero time is written as without layout, in UTC whatever the time zone
+	samples := []timeLayoutSample{{}}
+	out, err := MarshalString(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "created,shipped,received\n0001-01-01 00:00,,0001-01-01T00:00:00Z\n"
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+
+	var decoded []timeLayoutSample
+	if err := UnmarshalString(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || !decoded[0].Created.IsZero() || decoded[0].Shipped != nil || !decoded[0].Received.IsZero() {
+		t.Fatalf("expected zero times, got %+v", decoded)
+	}
+}
+
+func TestTimeLayoutTagsErrors(t *testing.T) {
+	var samples []timeLayoutSample
+	err := UnmarshalString("created\n2024-13-45", &samples)
+	if err == nil || !strings.Contains(err.Error(), "2024-13-45") {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+
+	type badZone struct {
+		Created time.Time `csv:"created,tz=Nowhere/Nothing"`
+	}
+	var bad []badZone
+	err = UnmarshalString("created\n2024-01-01T00:00:00Z", &bad)
+	if err == nil || !strings.Contains(err.Error(), "invalid time zone") {
+		t.Fatalf("expected an invalid time zone error, got %v", err)
+	}
+}
+
+type nullSample struct {
+	Name   *string         `csv:"name"`
+	Age    *int            `csv:"age"`
+	Score  int             `csv:"score"`
+	Nick   sql.NullString  `csv:"nick"`
+	Amount sql.NullFloat64 `csv:"amount"`
+	Count  sql.NullInt64   `csv:"count"`
+	Note   *string         `csv:"note,null=N/A"`
+}
+
+func TestNullTokens(t *testing.T) {
+	in := `name,age,score,nick,amount,count,note
+NULL,\N,NULL,NULL,\N,NULL,NULL
+bob,42,7,bobby,1.5,,N/A`
+	var samples []nullSample
+	if err := UnmarshalString(in, &samples, WithNullTokens("NULL", `\N`)); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(samples))
+	}
+	null := samples[0]
+	if null.Name != nil || null.Age != nil || null.Score != 0 || null.Nick.Valid || null.Amount.Valid || null.Count.Valid {
+		t.Fatalf("expected null values, got %+v", null)
+	}
+	if null.Note == nil || *null.Note != "NULL" {
+		t.Fatalf("expected the null tag option to override the config tokens, got %v", null.Note)
+	}
+	set := samples[1]
+	if *set.Name != "bob" || *set.Age != 42 || set.Score != 7 || set.Nick != (sql.NullString{String: "bobby", Valid: true}) ||
+		set.Amount != (sql.NullFloat64{Float64: 1.5, Valid: true}) || set.Count.Valid || set.Note != nil {
+		t.Fatalf("unexpected sample %+v", set)
+	}
+
+	out, err := MarshalString(samples, WithNullTokens("NULL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `name,age,score,nick,amount,count,note
+NULL,NULL,0,NULL,NULL,NULL,NULL
+bob,42,7,bobby,1.5,NULL,N/A
+`
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+}
+
+func TestNullTokensWithoutConfig(t *testing.T) {
+	var samples []nullSample
+	if err := UnmarshalString("name,score,nick,note\nNULL,0,,N/A", &samples); err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Name == nil || *samples[0].Name != "NULL" || samples[0].Nick.Valid || samples[0].Note != nil {
+		t.Fatalf("unexpected sample %+v", samples[0])
+	}
+	out, err := MarshalString(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "name,age,score,nick,amount,count,note\nNULL,,0,,,,N/A\n"; out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+}
+
+// valuerAddress implements sql.Scanner and driver.Valuer, for storing it in a
+// single database column, but is still expanded into nested CSV columns.
+type valuerAddress struct {
+	Street string `csv:"street"`
+	City   string `csv:"city"`
+}
+
+func (a valuerAddress) Value() (driver.Value, error) {
+	return a.Street + ", " + a.City, nil
+}
+
+func (a *valuerAddress) Scan(src interface{}) error {
+	return nil
+}
+
+func TestNullTokensKeepStructExpansion(t *testing.T) {
+	type sample struct {
+		Name    string         `csv:"name"`
+		Address valuerAddress  `csv:"address"`
+		Nick    sql.NullString `csv:"nick"`
+	}
+	in := "name,address.street,address.city,nick\nbob,Main St,Paris,NULL\n"
+	var samples []sample
+	if err := UnmarshalString(in, &samples, WithNullTokens("NULL")); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Address != (valuerAddress{"Main St", "Paris"}) || samples[0].Nick.Valid {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	out, err := MarshalString(samples, WithNullTokens("NULL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Fatalf("expected %q, got %q", in, out)
+	}
+}
+
+type splitSample struct {
+	Colors []string    `csv:"colors,split=|"`
+	Sizes  []int       `csv:"sizes,split=;"`
+	Dates  []time.Time `csv:"dates,split=|,layout=2006-01-02"`
+	Pair   [2]float64  `csv:"pair,split=/"`
+}
+
+func TestSplitTag(t *testing.T) {
+	in := `colors,sizes,dates,pair
+red|green\|blue|back\\slash,1;2;3,2024-01-02|2024-03-04,1.5/2
+,,,`
+	var samples []splitSample
+	if err := UnmarshalString(in, &samples); err != nil {
+		t.Fatal(err)
+	}
+	first := samples[0]
+	if !reflect.DeepEqual(first.Colors, []string{"red", "green|blue", `back\slash`}) {
+		t.Fatalf("unexpected colors %q", first.Colors)
+	}
+	if !reflect.DeepEqual(first.Sizes, []int{1, 2, 3}) || first.Pair != [2]float64{1.5, 2} {
+		t.Fatalf("unexpected sample %+v", first)
+	}
+	if len(first.Dates) != 2 || !first.Dates[1].Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the layout to apply to each element, got %v", first.Dates)
+	}
+	if len(samples[1].Colors) != 0 || len(samples[1].Sizes) != 0 {
+		t.Fatalf("expected empty slices, got %+v", samples[1])
+	}
+
+	out, err := MarshalString(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `colors,sizes,dates,pair
+red|green\|blue|back\\slash,1;2;3,2024-01-02|2024-03-04,1.5/2
+,,,0/0
+`
+	if out != expected {
+		t.Fatalf("expected %q, got %q", expected, out)
+	}
+
+	var bad []splitSample
+	if err := UnmarshalString("pair\n1/2/3", &bad); err == nil {
+		t.Fatal("expected an error when the array is too short")
+	}
+}
@@ -2,34 +2,62 @@ package gocsv
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"reflect"
 )
 
+// errCollectUnsupported is returned when creating an Unmarshaller in collect
+// mode, each Read returning the error of its record.
+var errCollectUnsupported = errors.New("the Unmarshaller does not support collect mode (WithCollectErrors)")
+
 // Unmarshaller is a CSV to struct unmarshaller.
 type Unmarshaller struct {
+	cfg                    *Config
 	reader                 *csv.Reader
 	Headers                []string
+	rawHeaders             []string // Headers as read, naming the columns kept by the remain field
 	fieldInfoMap           []*fieldInfo
+	remain                 *fieldInfo
 	MismatchedHeaders      []string
 	MismatchedStructFields []string
 	outType                reflect.Type
 	out                    interface{}
+	line                   int // Line of the last record read
 }
 
 // NewUnmarshaller creates an unmarshaller from a csv.Reader and a struct.
-func NewUnmarshaller(reader *csv.Reader, out interface{}) (*Unmarshaller, error) {
+func NewUnmarshaller(reader *csv.Reader, out interface{}, opts ...Option) (*Unmarshaller, error) {
 	headers, err := reader.Read()
 	if err != nil {
 		return nil, err
 	}
-	headers = normalizeHeaders(headers)
-
-	um := &Unmarshaller{reader: reader, outType: reflect.TypeOf(out)}
-	err = validate(um, out, headers)
+	um, err := newUnmarshaller(reader, headers, out, opts)
 	if err != nil {
 		return nil, err
 	}
+	um.line = 1 // account for the header
+	return um, nil
+}
+
+// NewUnmarshallerWithHeaders creates an unmarshaller from a csv.Reader and a
+// struct, the columns being named by headers: the first row is a record, not a header.
+func NewUnmarshallerWithHeaders(reader *csv.Reader, headers []string, out interface{}, opts ...Option) (*Unmarshaller, error) {
+	if len(headers) == 0 {
+		return nil, errNoHeaders
+	}
+	return newUnmarshaller(reader, headers, out, opts)
+}
+
+func newUnmarshaller(reader *csv.Reader, headers []string, out interface{}, opts []Option) (*Unmarshaller, error) {
+	cfg := newConfig(opts)
+	if cfg.CollectErrors {
+		return nil, errCollectUnsupported
+	}
+	um := &Unmarshaller{cfg: cfg, reader: reader, rawHeaders: headers, outType: reflect.TypeOf(out)}
+	if err := validate(um, out, normalizeHeaders(cfg, headers)); err != nil {
+		return nil, err
+	}
 	return um, nil
 }
 
@@ -37,6 +65,7 @@ func NewUnmarshaller(reader *csv.Reader, out interface{}) (*Unmarshaller, error)
 // was used to create the Unmarshaller.
 func (um *Unmarshaller) Read() (interface{}, error) {
 	row, err := um.reader.Read()
+	um.line++
 	if err != nil {
 		return nil, err
 	}
@@ -46,6 +75,7 @@ func (um *Unmarshaller) Read() (interface{}, error) {
 // ReadUnmatched is same as Read(), but returns a map of the columns that didn't match a field in the struct
 func (um *Unmarshaller) ReadUnmatched() (interface{}, map[string]string, error) {
 	row, err := um.reader.Read()
+	um.line++
 	if err != nil {
 		return nil, nil, err
 	}
@@ -64,7 +94,8 @@ func validate(um *Unmarshaller, s interface{}, headers []string) error {
 	if err := ensureOutInnerType(concreteType); err != nil {
 		return err
 	}
-	structInfo := getStructInfo(concreteType) // Get struct info to get CSV annotations.
+	structInfo := getStructInfo(um.cfg, concreteType) // Get struct info to get CSV annotations.
+	structInfo = expandDynamicFieldsForHeaders(um.cfg, structInfo, headers)
 	if len(structInfo.Fields) == 0 {
 		return ErrNoStructTags
 	}
@@ -74,21 +105,25 @@ func validate(um *Unmarshaller, s interface{}, headers []string) error {
 		curHeaderCount := headerCount[csvColumnHeader]
 		if fieldInfo := getCSVFieldPosition(csvColumnHeader, structInfo, curHeaderCount); fieldInfo != nil {
 			csvHeadersLabels[i] = fieldInfo
-			if ShouldAlignDuplicateHeadersWithStructFieldOrder {
+			if um.cfg.ShouldAlignDuplicateHeadersWithStructFieldOrder {
 				curHeaderCount++
 				headerCount[csvColumnHeader] = curHeaderCount
 			}
 		}
 	}
 
-	if FailIfDoubleHeaderNames {
+	if um.cfg.FailIfDoubleHeaderNames {
 		if err := maybeDoubleHeaderNames(headers); err != nil {
 			return err
 		}
 	}
+	if err := checkRequiredColumns(concreteType, structInfo.Fields, headers); err != nil {
+		return err
+	}
 
 	um.Headers = headers
 	um.fieldInfoMap = csvHeadersLabels
+	um.remain = structInfo.remain
 	um.MismatchedHeaders = mismatchHeaderFields(structInfo.Fields, headers)
 	um.MismatchedStructFields = mismatchStructFields(structInfo.Fields, headers)
 	um.out = s
@@ -104,15 +139,30 @@ func (um *Unmarshaller) unmarshalRow(row []string, unmatched map[string]string)
 		isPointer = true
 		concreteOutType = concreteOutType.Elem()
 	}
+	if um.cfg.FailIfShortRecords {
+		if err := checkShortRecord(row, um.Headers, um.line); err != nil {
+			return nil, err
+		}
+	}
 	outValue := createNewOutInner(isPointer, concreteOutType)
 	for j, csvColumnContent := range row {
 		if j < len(um.fieldInfoMap) && um.fieldInfoMap[j] != nil {
 			fieldInfo := um.fieldInfoMap[j]
-			if err := setInnerField(&outValue, isPointer, fieldInfo.IndexChain, csvColumnContent, fieldInfo.omitEmpty); err != nil { // Set field of struct
+			if err := setInnerField(&outValue, isPointer, fieldInfo.IndexChain, csvColumnContent, fieldInfo); err != nil { // Set field of struct
 				return nil, fmt.Errorf("cannot assign field at %v to %s through index chain %v: %v", j, outValue.Type(), fieldInfo.IndexChain, err)
 			}
-		} else if unmatched != nil {
-			unmatched[um.Headers[j]] = csvColumnContent
+			if err := checkValidationRules(outValue, concreteOutType, fieldInfo, csvColumnContent, um.line, j+1); err != nil {
+				return nil, &csv.ParseError{StartLine: um.line, Line: um.line, Column: j + 1, Err: err}
+			}
+		} else if j < len(um.Headers) {
+			if unmatched != nil {
+				unmatched[um.Headers[j]] = csvColumnContent
+			}
+			if um.remain != nil {
+				if err := setRemainField(outValue, um.remain, um.rawHeaders[j], csvColumnContent); err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 	return outValue.Interface(), nil
@@ -0,0 +1,224 @@
+package gocsv
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// --------------------------------------------------------------------------
+// Validation rules
+//
+// The following options can be added to a csv tag to validate each decoded value:
+//
+//	required       the cell must not be empty
+//	min=N, max=N   bounds of a number, or of the length of a string or slice
+//	len=N          exact length of a string or slice, or exact value of a number
+//	oneof=a|b|c    the cell must be one of the listed values
+//	regex=EXPR     the cell must match the regular expression
+//
+// Apart from required, rules are not checked against empty or null cells. As tag
+// options are split on TagSeparator, the parameters cannot contain it.
+
+// ValidationError is the error reported, wrapped in a *csv.ParseError, when a
+// decoded value does not satisfy a validation rule of its struct tag. As the
+// wrapping error gives the position of the cell, Error leaves it out. It is
+// returned as is, with zero Line and Column, when no column of the header
+// matches a required field.
+type ValidationError struct {
+	Line   int    // Line of the record, starting at 1
+	Column int    // Column of the cell, starting at 1, or 0 when the column is missing
+	Field  string // Path of the struct field
+	Rule   string // Failing rule, as written in the tag, e.g. "min=3"
+	Value  string // Raw content of the cell
+}
+
+func (e *ValidationError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("field %s does not satisfy %q: no column of the header matches it", e.Field, e.Rule)
+	}
+	return fmt.Sprintf("value %q of field %s does not satisfy %q", e.Value, e.Field, e.Rule)
+}
+
+type validationRule struct {
+	rule   string // as written in the tag
+	name   string
+	number float64
+	values []string
+	regex  *regexp.Regexp
+	err    error // set when the rule parameter is invalid
+}
+
+var validationRuleNames = []string{"required", "min=", "max=", "len=", "oneof=", "regex="}
+
+// isValidationRule tells whether a tag entry is a validation rule rather than a column name.
+func isValidationRule(tagEntry string) bool {
+	for _, name := range validationRuleNames {
+		if tagEntry == name || (strings.HasSuffix(name, "=") && strings.HasPrefix(tagEntry, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRule tells whether the field has the validation rule of the given name.
+func (f fieldInfo) hasRule(name string) bool {
+	for _, rule := range f.rules {
+		if rule.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parseValidationRule(tagEntry string) validationRule {
+	name, param := tagEntry, ""
+	if i := strings.Index(tagEntry, "="); i >= 0 {
+		name, param = tagEntry[:i], tagEntry[i+1:]
+	}
+	rule := validationRule{rule: tagEntry, name: name}
+	switch name {
+	case "min", "max", "len":
+		rule.number, rule.err = strconv.ParseFloat(param, 64)
+	case "oneof":
+		rule.values = strings.Split(param, "|")
+	case "regex":
+		rule.regex, rule.err = regexp.Compile(param)
+	}
+	if rule.err != nil {
+		rule.err = fmt.Errorf("invalid validation rule %q: %w", tagEntry, rule.err)
+	}
+	return rule
+}
+
+// checkValidationRules checks the value just decoded from the cell value into
+// the field of outInner described by fieldInfo.
+func checkValidationRules(outInner reflect.Value, rType reflect.Type, fieldInfo *fieldInfo, value string, line, column int) error {
+	for _, rule := range fieldInfo.rules {
+		if rule.err != nil {
+			return rule.err
+		}
+		if value == "" || fieldInfo.isNull(value) {
+			if rule.name != "required" {
+				continue
+			}
+		} else if rule.check(getValidatedValue(outInner, fieldInfo), value) {
+			continue
+		}
+		return &ValidationError{
+			Line:   line,
+			Column: column,
+			Field:  getFieldPath(rType, fieldInfo.IndexChain),
+			Rule:   rule.rule,
+			Value:  value,
+		}
+	}
+	return nil
+}
+
+// checkRequiredColumns reports the first field with a required rule that no
+// header matches, as its cells would never be checked.
+func checkRequiredColumns(rType reflect.Type, fields []fieldInfo, headers []string) error {
+	for _, info := range fields {
+		if !info.hasRule("required") {
+			continue
+		}
+		found := false
+		for _, header := range headers {
+			if info.matchesKey(header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &ValidationError{Field: getFieldPath(rType, info.IndexChain), Rule: "required"}
+		}
+	}
+	return nil
+}
+
+func (rule validationRule) check(field reflect.Value, value string) bool {
+	switch rule.name {
+	case "required":
+		return value != ""
+	case "oneof":
+		for _, v := range rule.values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	case "regex":
+		return rule.regex.MatchString(value)
+	}
+
+	for field.IsValid() && field.Kind() == reflect.Ptr {
+		field = field.Elem()
+	}
+	if !field.IsValid() {
+		return true
+	}
+	var n float64
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(field.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = field.Float()
+	case reflect.String:
+		n = float64(utf8.RuneCountInString(field.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n = float64(field.Len())
+	default:
+		n = float64(utf8.RuneCountInString(value))
+	}
+	switch rule.name {
+	case "min":
+		return n >= rule.number
+	case "max":
+		return n <= rule.number
+	case "len":
+		return n == rule.number
+	}
+	return true
+}
+
+// getValidatedValue returns the value decoded for fieldInfo: its field, or
+// the map entry of its column for a prefix map field.
+func getValidatedValue(outInner reflect.Value, fieldInfo *fieldInfo) reflect.Value {
+	field := getInnerFieldValue(outInner, fieldInfo.IndexChain)
+	if field.IsValid() && fieldInfo.isMapEntry(field) && !field.IsNil() {
+		return field.MapIndex(reflect.ValueOf(fieldInfo.mapKey).Convert(field.Type().Key()))
+	}
+	return field
+}
+
+// getInnerFieldValue returns the field of outInner reached by index, or an
+// invalid value when a nil pointer or a too short slice is on the way.
+func getInnerFieldValue(outInner reflect.Value, index []int) reflect.Value {
+	v := outInner
+	for _, i := range index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.Field(i)
+		case reflect.Slice, reflect.Array:
+			if i >= v.Len() {
+				return reflect.Value{}
+			}
+			v = v.Index(i)
+		default:
+			return reflect.Value{}
+		}
+	}
+	return v
+}
@@ -0,0 +1,168 @@
+package gocsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type validationSample struct {
+	ID     string  `csv:"id,required,len=4"`
+	Age    int     `csv:"age,min=18,max=99"`
+	Name   string  `csv:"name,min=2,max=5"`
+	Status string  `csv:"status,oneof=open|closed"`
+	Code   string  `csv:"code,regex=^[A-Z]+$"`
+	Score  *int    `csv:"score,max=10"`
+	Ratio  float64 `csv:"ratio,omitempty,max=1"`
+}
+
+func TestValidationRules(t *testing.T) {
+	var samples []validationSample
+	in := "id,age,name,status,code,score,ratio\nab12,30,Bob,open,AB,3,0.5\nxy34,18,Al,,,,"
+	if err := UnmarshalString(in, &samples); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Age != 30 || *samples[0].Score != 3 || samples[1].Name != "Al" {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+
+	tests := []struct {
+		in     string
+		column int
+		field  string
+		rule   string
+	}{
+		{"id,age\n,30", 1, "ID", "required"},
+		{"id,age\nabc,30", 1, "ID", "len=4"},
+		{"id,age\nab12,17", 2, "Age", "min=18"},
+		{"id,age\nab12,100", 2, "Age", "max=99"},
+		{"id,name\nab12,A", 2, "Name", "min=2"},
+		{"id,name\nab12,Élodie", 2, "Name", "max=5"},
+		{"id,status\nab12,pending", 2, "Status", "oneof=open|closed"},
+		{"id,code\nab12,ab", 2, "Code", "regex=^[A-Z]+$"},
+		{"id,score\nab12,11", 2, "Score", "max=10"},
+		{"id,ratio\nab12,1.5", 2, "Ratio", "max=1"},
+	}
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			var samples []validationSample
+			err := UnmarshalString(test.in, &samples)
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) || parseErr.Line != 2 || parseErr.Column != test.column {
+				t.Fatalf("expected a *csv.ParseError at line 2, column %d, got %v", test.column, err)
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a *ValidationError, got %v", err)
+			}
+			if validationErr.Line != 2 || validationErr.Column != test.column || validationErr.Field != test.field || validationErr.Rule != test.rule {
+				t.Fatalf("unexpected validation error %+v", validationErr)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	var samples []validationSample
+	err := UnmarshalString("id,age\nab12,17", &samples)
+	expected := `parse error on line 2, column 2: value "17" of field Age does not satisfy "min=18"`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestUnmarshallerValidationRules(t *testing.T) {
+	um, err := NewUnmarshaller(csv.NewReader(strings.NewReader("id,age\nab12,30\nab12,17")), validationSample{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := um.Read(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = um.Read()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Line != 3 || validationErr.Column != 2 {
+		t.Fatalf("expected a validation error at line 3, column 2, got %v", err)
+	}
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 3 {
+		t.Fatalf("expected a *csv.ParseError at line 3, got %v", err)
+	}
+
+	um, err = NewUnmarshallerWithHeaders(csv.NewReader(strings.NewReader("ab12,17")), []string{"id", "age"}, validationSample{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := um.Read(); !errors.As(err, &validationErr) || validationErr.Line != 1 {
+		t.Fatalf("expected a validation error at line 1, got %v", err)
+	}
+}
+
+func TestValidationRulesInvalidParameter(t *testing.T) {
+	type sample struct {
+		Age int `csv:"age,min=ten"`
+	}
+	var samples []sample
+	err := UnmarshalString("age\n12", &samples)
+	if err == nil || !strings.Contains(err.Error(), `invalid validation rule "min=ten"`) {
+		t.Fatalf("expected an invalid rule error, got %v", err)
+	}
+}
+
+func TestValidationRulesCollectErrors(t *testing.T) {
+	var samples []validationSample
+	err := UnmarshalString("id,age\nab12,10\nab,30\nab12,x", &samples, WithCollectErrors(0))
+	var decodeErrs *DecodeErrors
+	if !errors.As(err, &decodeErrs) || len(decodeErrs.Errors) != 3 {
+		t.Fatalf("expected 3 collected errors, got %v", err)
+	}
+	var validationErr *ValidationError
+	if !errors.As(decodeErrs.Errors[1], &validationErr) || validationErr.Rule != "len=4" || validationErr.Line != 3 {
+		t.Fatalf("unexpected second error %v", decodeErrs.Errors[1])
+	}
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(samples))
+	}
+}
+
+func TestValidationRulesWithoutHeaders(t *testing.T) {
+	type sample struct {
+		Name string `csv:"name,required"`
+		Age  int    `csv:"age,min=1"`
+	}
+	var samples []sample
+	err := UnmarshalWithoutHeaders(strings.NewReader("a,0"), &samples)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Line != 1 || validationErr.Column != 2 {
+		t.Fatalf("expected a validation error at line 1, column 2, got %v", err)
+	}
+}
+
+func TestValidationRulesRequiredColumn(t *testing.T) {
+	var samples []validationSample
+	err := UnmarshalString("age,name\n30,Bob", &samples)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "ID" || validationErr.Rule != "required" || validationErr.Column != 0 {
+		t.Fatalf("expected a required validation error for the missing id column, got %v", err)
+	}
+
+	_, err = NewUnmarshaller(csv.NewReader(strings.NewReader("age,name\n30,Bob")), validationSample{})
+	if !errors.As(err, &validationErr) || validationErr.Field != "ID" {
+		t.Fatalf("expected a required validation error from the unmarshaller, got %v", err)
+	}
+}
+
+func TestValidationRuleColumnName(t *testing.T) {
+	type sample struct {
+		Required string `csv:"required"`
+		Min      string `csv:"min=1,required"`
+	}
+	var samples []sample
+	if err := UnmarshalString("required,min=1\nyes,a", &samples); err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Required != "yes" || samples[0].Min != "a" {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+}